@@ -4,47 +4,70 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/cedaesca/alicia/internal/app"
 )
 
 var (
-	Token string
+	Options app.Options
 )
 
 func parseAndValidateConfig() error {
-	token, err := parseAndValidateConfigFrom(flag.CommandLine, os.Args[1:])
+	options, err := parseAndValidateConfigFrom(flag.CommandLine, os.Args[1:])
 	if err != nil {
 		return err
 	}
 
-	Token = token
+	Options = options
 	return nil
 }
 
-func parseAndValidateConfigFrom(flagSet *flag.FlagSet, args []string) (string, error) {
+func parseAndValidateConfigFrom(flagSet *flag.FlagSet, args []string) (app.Options, error) {
 	if flagSet == nil {
-		return "", errors.New("missing flag set")
+		return app.Options{}, errors.New("missing flag set")
 	}
 
-	var token string
-	flagSet.StringVar(&token, "t", "", "Bot Token")
+	var options app.Options
+	var devGuilds string
+	flagSet.StringVar(&options.Token, "t", "", "Bot Token")
+	flagSet.StringVar(&options.Store, "store", app.StoreJSON, "Notification store backend: json or sqlite")
+	flagSet.StringVar(&devGuilds, "dev-guilds", "", "Comma-separated guild IDs to register commands in instead of globally, for development")
 
 	if err := flagSet.Parse(args); err != nil {
-		return "", err
+		return app.Options{}, err
 	}
 
-	if strings.TrimSpace(token) == "" {
-		return "", errors.New("missing bot token: pass it with -t")
+	if strings.TrimSpace(options.Token) == "" {
+		return app.Options{}, errors.New("missing bot token: pass it with -t")
 	}
 
-	return token, nil
+	if options.Store != app.StoreJSON && options.Store != app.StoreSQLite {
+		return app.Options{}, fmt.Errorf("invalid store %q: use json or sqlite", options.Store)
+	}
+
+	for _, guildID := range strings.Split(devGuilds, ",") {
+		guildID = strings.TrimSpace(guildID)
+		if guildID == "" {
+			continue
+		}
+
+		if _, err := strconv.ParseUint(guildID, 10, 64); err != nil {
+			return app.Options{}, fmt.Errorf("invalid dev guild id %q: use numeric guild IDs", guildID)
+		}
+
+		options.DevGuildIDs = append(options.DevGuildIDs, guildID)
+	}
+
+	return options, nil
 }
 
 func gracefulShutdown(application *app.Application, done chan bool) {
@@ -73,7 +96,7 @@ func main() {
 	}
 
 	ctx := context.Background()
-	application, err := app.NewApplication(ctx, Token)
+	application, err := app.NewApplication(ctx, Options)
 	if err != nil {
 		log.Fatalf("failed to create application: %v", err)
 	}
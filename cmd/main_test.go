@@ -3,26 +3,76 @@ package main
 import (
 	"flag"
 	"testing"
+
+	"github.com/cedaesca/alicia/internal/app"
 )
 
 func TestParseAndValidateConfigFrom(t *testing.T) {
 	t.Run("valid token", func(t *testing.T) {
 		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
 
-		token, err := parseAndValidateConfigFrom(flagSet, []string{"-t", "abc123"})
+		options, err := parseAndValidateConfigFrom(flagSet, []string{"-t", "abc123"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if options.Token != "abc123" {
+			t.Fatalf("expected token abc123, got %q", options.Token)
+		}
+
+		if options.Store != app.StoreJSON {
+			t.Fatalf("expected default store json, got %q", options.Store)
+		}
+	})
+
+	t.Run("sqlite store", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+
+		options, err := parseAndValidateConfigFrom(flagSet, []string{"-t", "abc123", "-store", "sqlite"})
 		if err != nil {
 			t.Fatalf("expected nil error, got %v", err)
 		}
 
-		if token != "abc123" {
-			t.Fatalf("expected token abc123, got %q", token)
+		if options.Store != app.StoreSQLite {
+			t.Fatalf("expected sqlite store, got %q", options.Store)
+		}
+	})
+
+	t.Run("unknown store", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+
+		_, err := parseAndValidateConfigFrom(flagSet, []string{"-t", "abc123", "-store", "mysql"})
+		if err == nil || err.Error() != `invalid store "mysql": use json or sqlite` {
+			t.Fatalf("expected invalid store error, got %v", err)
+		}
+	})
+
+	t.Run("dev guilds", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+
+		options, err := parseAndValidateConfigFrom(flagSet, []string{"-t", "abc123", "-dev-guilds", "123, 456,"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if len(options.DevGuildIDs) != 2 || options.DevGuildIDs[0] != "123" || options.DevGuildIDs[1] != "456" {
+			t.Fatalf("expected dev guilds 123 and 456, got %v", options.DevGuildIDs)
+		}
+	})
+
+	t.Run("invalid dev guild", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+
+		_, err := parseAndValidateConfigFrom(flagSet, []string{"-t", "abc123", "-dev-guilds", "abc"})
+		if err == nil || err.Error() != `invalid dev guild id "abc": use numeric guild IDs` {
+			t.Fatalf("expected invalid dev guild error, got %v", err)
 		}
 	})
 
 	t.Run("missing token", func(t *testing.T) {
 		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
 
-		token, err := parseAndValidateConfigFrom(flagSet, []string{})
+		options, err := parseAndValidateConfigFrom(flagSet, []string{})
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -31,8 +81,8 @@ func TestParseAndValidateConfigFrom(t *testing.T) {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		if token != "" {
-			t.Fatalf("expected empty token, got %q", token)
+		if options.Token != "" {
+			t.Fatalf("expected empty token, got %q", options.Token)
 		}
 	})
 
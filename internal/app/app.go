@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
-	"time"
 
+	"github.com/cedaesca/alicia/internal/clock"
 	"github.com/cedaesca/alicia/internal/commands"
+	"github.com/cedaesca/alicia/internal/datafile"
 	"github.com/cedaesca/alicia/internal/discord"
 	"github.com/cedaesca/alicia/internal/scheduler"
 )
@@ -19,10 +22,26 @@ import (
 const commandStateFileName = "discord_commands.json"
 const notificationConfigFileName = "notification_config.json"
 const notificationsFileName = "notifications.json"
+const sqliteDatabaseFileName = "alicia.db"
 const dataDirectoryName = "data"
 
+// commandStateSchemaVersion is the current version of discord_commands.json.
+const commandStateSchemaVersion = 1
+
 type commandState struct {
-	Commands map[string]string `json:"commands"`
+	SchemaVersion int               `json:"schema_version"`
+	Commands      map[string]string `json:"commands"`
+	// Guilds records the commands registered in each development guild.
+	Guilds map[string]map[string]string `json:"guilds,omitempty"`
+}
+
+// commandStateMigrations upgrades older discord_commands.json files on load.
+var commandStateMigrations = datafile.Registry{
+	Current: commandStateSchemaVersion,
+	Steps: map[int]datafile.Step{
+		// Version 1 only introduced schema_version itself.
+		0: func(content []byte) ([]byte, error) { return datafile.WithVersion(content, 1) },
+	},
 }
 
 type notificationConfigCountState struct {
@@ -33,21 +52,40 @@ type notificationScheduleCountState struct {
 	Notifications []json.RawMessage `json:"notifications"`
 }
 
+const (
+	StoreJSON   = "json"
+	StoreSQLite = "sqlite"
+)
+
+// Options configures the application at startup.
+type Options struct {
+	Token string
+	// Store selects the notification store backend: StoreJSON (the default)
+	// or StoreSQLite.
+	Store string
+	// DevGuildIDs, when set, registers every command in these guilds instead
+	// of globally, so changes show up immediately while developing.
+	DevGuildIDs []string
+}
+
 type Application struct {
 	ctx                 context.Context
 	logger              *log.Logger
 	discordClient       discord.Client
 	commands            map[string]commands.Command
+	configStore         commands.NotificationConfigStore
 	stateFilePath       string
+	devGuildIDs         []string
 	notificationService *scheduler.NotificationService
+	closeStore          func() error
 }
 
-func NewApplication(ctx context.Context, token string) (*Application, error) {
+func NewApplication(ctx context.Context, options Options) (*Application, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	if strings.TrimSpace(token) == "" {
+	if strings.TrimSpace(options.Token) == "" {
 		return nil, errors.New("missing bot token")
 	}
 
@@ -63,15 +101,21 @@ func NewApplication(ctx context.Context, token string) (*Application, error) {
 	resolvedNotificationConfigFilePath := resolveDataFilePath(executablePath, notificationConfigFileName)
 	logDataFolderStatusAndCounts(logger, resolvedNotificationConfigFilePath)
 
-	discordClient, err := discord.NewDiscordGoClient(token)
+	discordClient, err := discord.NewDiscordGoClient(options.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	systemClock := clock.System()
+	configStore, closeStore, err := openNotificationStore(logger, options.Store, executablePath, systemClock)
 	if err != nil {
 		return nil, err
 	}
 
-	configStore := commands.NewJSONNotificationConfigStore(resolvedNotificationConfigFilePath)
+	notificationService := scheduler.NewNotificationService(ctx, logger, discordClient, configStore, systemClock)
 
 	registeredCommands := make(map[string]commands.Command)
-	for _, command := range commands.All(configStore, discordClient) {
+	for _, command := range commands.All(scheduler.WakeOnChange(configStore, notificationService), discordClient, systemClock) {
 		definition := command.Definition()
 		registeredCommands[definition.Name] = command
 	}
@@ -81,11 +125,44 @@ func NewApplication(ctx context.Context, token string) (*Application, error) {
 		logger:              logger,
 		discordClient:       discordClient,
 		commands:            registeredCommands,
+		configStore:         configStore,
 		stateFilePath:       resolvedStateFilePath,
-		notificationService: scheduler.NewNotificationService(ctx, logger, discordClient, configStore),
+		devGuildIDs:         options.DevGuildIDs,
+		notificationService: notificationService,
+		closeStore:          closeStore,
 	}, nil
 }
 
+// openNotificationStore opens the selected store backend and returns it with
+// a function that releases it on shutdown.
+func openNotificationStore(logger *log.Logger, backend, executablePath string, clk clock.Clock) (commands.NotificationConfigStore, func() error, error) {
+	switch backend {
+	case "", StoreJSON:
+		filePath := resolveDataFilePath(executablePath, notificationConfigFileName)
+		repairs, err := commands.MigrateJSONNotificationStore(filePath, clk)
+		if err != nil {
+			return nil, nil, fmt.Errorf("migrate json store: %w", err)
+		}
+
+		for _, repair := range repairs {
+			logger.Printf("repaired notification store drift: %s", repair)
+		}
+
+		return commands.NewJSONNotificationConfigStore(filePath, clk), func() error { return nil }, nil
+	case StoreSQLite:
+		filePath := resolveDataFilePath(executablePath, sqliteDatabaseFileName)
+		store, err := commands.NewSQLiteNotificationConfigStore(filePath, clk)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open sqlite store: %w", err)
+		}
+
+		logger.Printf("Using SQLite notification store: %s", filePath)
+		return store, store.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown notification store %q", backend)
+	}
+}
+
 func resolveDataFilePath(executablePath string, fileName string) string {
 	dataDir := dataDirectoryName
 	if strings.TrimSpace(executablePath) != "" {
@@ -122,6 +199,11 @@ func logDataFolderStatusAndCounts(logger *log.Logger, notificationConfigFilePath
 func readGuildAndNotificationCounts(notificationConfigFilePath, notificationsFilePath string) (int, int, error) {
 	guildCount, guildErr := readGuildCount(notificationConfigFilePath)
 	notificationCount, notificationErr := readNotificationCount(notificationsFilePath)
+	// The JSON store keeps notifications in the config file once the legacy
+	// notifications.json has been migrated into it.
+	if notificationErr == nil && notificationCount == 0 {
+		notificationCount, notificationErr = readNotificationCount(notificationConfigFilePath)
+	}
 
 	if guildErr == nil && notificationErr == nil {
 		return guildCount, notificationCount, nil
@@ -209,7 +291,7 @@ func (application *Application) Run() error {
 	}
 
 	if application.notificationService != nil {
-		if err := application.notificationService.RecalculateSchedules(application.ctx, time.Now().UTC()); err != nil {
+		if err := application.notificationService.RecalculateSchedules(application.ctx); err != nil {
 			_ = application.discordClient.Close()
 			return fmt.Errorf("recalculate notification schedules: %w", err)
 		}
@@ -230,6 +312,12 @@ func (application *Application) Shutdown(ctx context.Context) error {
 		application.notificationService.Stop()
 	}
 
+	if application.closeStore != nil {
+		if err := application.closeStore(); err != nil {
+			application.logger.Printf("failed to close notification store: %v", err)
+		}
+	}
+
 	errChan := make(chan error, 1)
 	go func() {
 		errChan <- application.discordClient.Close()
@@ -250,12 +338,30 @@ func (application *Application) Shutdown(ctx context.Context) error {
 func (application *Application) registerCommandHandler() {
 	application.discordClient.AddInteractionCreateHandler(func(interaction discord.Interaction) {
 		command, ok := application.commands[interaction.CommandName]
+		if interaction.Autocomplete {
+			application.respondWithSuggestions(command, interaction)
+			return
+		}
+
 		if !ok {
 			application.logger.Printf("unknown command received: %s", interaction.CommandName)
 			_ = application.discordClient.RespondToInteraction(interaction, "Unknown command")
 			return
 		}
 
+		if err := commands.Authorize(application.ctx, application.configStore, command, interaction); err != nil {
+			var denied *commands.PermissionDeniedError
+			if !errors.As(err, &denied) {
+				application.logger.Printf("failed to authorize command %s: %v", interaction.CommandName, err)
+				_ = application.discordClient.RespondToInteraction(interaction, "Something went wrong")
+				return
+			}
+
+			application.logger.Printf("denied command %s to user %s in guild %s", interaction.CommandName, interaction.UserID, interaction.GuildID)
+			_ = application.discordClient.RespondToInteraction(interaction, denied.Error())
+			return
+		}
+
 		response, err := command.Execute(application.ctx, interaction)
 		if err != nil {
 			application.logger.Printf("failed to execute command %s: %v", interaction.CommandName, err)
@@ -269,41 +375,188 @@ func (application *Application) registerCommandHandler() {
 	})
 }
 
+// respondWithSuggestions answers an autocomplete interaction. Suggestions are
+// only given to members allowed to run the command, since they list the
+// notifications of the guild. Failures are logged and answered with no
+// suggestions, so the user can keep typing.
+func (application *Application) respondWithSuggestions(command commands.Command, interaction discord.Interaction) {
+	var choices []discord.AutocompleteChoice
+	if autocomplete, ok := command.(commands.AutocompleteCommand); ok {
+		if err := commands.Authorize(application.ctx, application.configStore, command, interaction); err != nil {
+			application.logger.Printf("no suggestions for command %s to user %s in guild %s: %v", interaction.CommandName, interaction.UserID, interaction.GuildID, err)
+		} else {
+			suggested, err := autocomplete.Autocomplete(application.ctx, interaction)
+			if err != nil {
+				application.logger.Printf("failed to autocomplete command %s: %v", interaction.CommandName, err)
+			}
+
+			choices = suggested
+		}
+	} else {
+		application.logger.Printf("autocomplete received for command without suggestions: %s", interaction.CommandName)
+	}
+
+	if err := application.discordClient.RespondWithAutocompleteChoices(interaction, choices); err != nil {
+		application.logger.Printf("failed to send suggestions for command %s: %v", interaction.CommandName, err)
+	}
+}
+
+// commandScope declares commands either globally or in a single guild.
+type commandScope struct {
+	name      string
+	list      func() ([]discord.RegisteredSlashCommand, error)
+	overwrite func(commands []discord.SlashCommand) ([]discord.RegisteredSlashCommand, error)
+}
+
+func (application *Application) globalCommandScope() commandScope {
+	client := application.discordClient
+	return commandScope{
+		name:      "global",
+		list:      client.ListGlobalCommands,
+		overwrite: client.BulkOverwriteGlobalCommands,
+	}
+}
+
+func (application *Application) guildCommandScope(guildID string) commandScope {
+	client := application.discordClient
+	return commandScope{
+		name: "guild " + guildID,
+		list: func() ([]discord.RegisteredSlashCommand, error) {
+			return client.ListGuildCommands(guildID)
+		},
+		overwrite: func(commands []discord.SlashCommand) ([]discord.RegisteredSlashCommand, error) {
+			return client.BulkOverwriteGuildCommands(guildID, commands)
+		},
+	}
+}
+
+// syncSlashCommands declares the local command definitions in Discord,
+// globally or, in development, in each development guild. Global commands are
+// left untouched in development. discord_commands.json only records the
+// resulting IDs.
 func (application *Application) syncSlashCommands() error {
 	state, err := application.loadCommandState()
 	if err != nil {
 		return err
 	}
 
-	loadedFromState := 0
-	registeredNow := 0
-
-	for name, command := range application.commands {
-		if _, ok := state.Commands[name]; ok {
-			loadedFromState++
-			continue
+	if len(application.devGuildIDs) == 0 {
+		commandIDs, err := application.syncCommandScope(application.globalCommandScope())
+		if err != nil {
+			return err
 		}
 
-		commandID, err := application.discordClient.RegisterGlobalCommand(command.Definition())
+		state.Commands = commandIDs
+		return application.saveCommandState(state)
+	}
+
+	application.logger.Printf("registering slash commands in development guilds: %s", strings.Join(application.devGuildIDs, ", "))
+	state.Guilds = make(map[string]map[string]string, len(application.devGuildIDs))
+	for _, guildID := range application.devGuildIDs {
+		commandIDs, err := application.syncCommandScope(application.guildCommandScope(guildID))
 		if err != nil {
 			return err
 		}
 
-		state.Commands[name] = commandID
-		registeredNow++
-		application.logger.Printf("registered slash command: %s", name)
+		state.Guilds[guildID] = commandIDs
 	}
 
-	application.logger.Printf("slash commands ready: loaded=%d registered=%d", loadedFromState, registeredNow)
-
 	return application.saveCommandState(state)
 }
 
+// syncCommandScope logs how the registered commands differ from the local
+// definitions and, when they differ, replaces them all in a single bulk
+// overwrite. It returns the ID of each command.
+func (application *Application) syncCommandScope(scope commandScope) (map[string]string, error) {
+	registered, err := scope.list()
+	if err != nil {
+		return nil, fmt.Errorf("list %s commands: %w", scope.name, err)
+	}
+
+	definitions := make([]discord.SlashCommand, 0, len(application.commands))
+	for _, name := range slices.Sorted(maps.Keys(application.commands)) {
+		definitions = append(definitions, application.commands[name].Definition())
+	}
+
+	diff := diffSlashCommands(definitions, registered)
+	if diff.empty() {
+		application.logger.Printf("slash commands up to date (%s): %d commands", scope.name, len(definitions))
+		return commandIDsByName(registered), nil
+	}
+
+	application.logger.Printf("slash commands changed (%s): added=%v changed=%v removed=%v", scope.name, diff.added, diff.changed, diff.removed)
+
+	overwritten, err := scope.overwrite(definitions)
+	if err != nil {
+		return nil, fmt.Errorf("overwrite %s commands: %w", scope.name, err)
+	}
+
+	application.logger.Printf("slash commands ready (%s): %d commands", scope.name, len(overwritten))
+	return commandIDsByName(overwritten), nil
+}
+
+// slashCommandDiff names the commands a sync adds, changes and removes.
+type slashCommandDiff struct {
+	added   []string
+	changed []string
+	removed []string
+}
+
+func (diff slashCommandDiff) empty() bool {
+	return len(diff.added) == 0 && len(diff.changed) == 0 && len(diff.removed) == 0
+}
+
+func diffSlashCommands(definitions []discord.SlashCommand, registered []discord.RegisteredSlashCommand) slashCommandDiff {
+	remote := make(map[string]discord.RegisteredSlashCommand, len(registered))
+	for _, command := range registered {
+		remote[command.Name] = command
+	}
+
+	var diff slashCommandDiff
+	local := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		local[definition.Name] = true
+		existing, ok := remote[definition.Name]
+		switch {
+		case !ok:
+			diff.added = append(diff.added, definition.Name)
+		case !existing.Equal(definition):
+			diff.changed = append(diff.changed, definition.Name)
+		}
+	}
+
+	for _, command := range registered {
+		if !local[command.Name] {
+			diff.removed = append(diff.removed, command.Name)
+		}
+	}
+
+	return diff
+}
+
+func commandIDsByName(commands []discord.RegisteredSlashCommand) map[string]string {
+	commandIDs := make(map[string]string, len(commands))
+	for _, command := range commands {
+		commandIDs[command.Name] = command.ID
+	}
+
+	return commandIDs
+}
+
 func (application *Application) loadCommandState() (commandState, error) {
+	migrated, err := datafile.Migrate(application.stateFilePath, commandStateMigrations)
+	if err != nil {
+		return commandState{}, err
+	}
+
+	if migrated {
+		application.logger.Printf("migrated %s to schema version %d", application.stateFilePath, commandStateSchemaVersion)
+	}
+
 	content, err := os.ReadFile(application.stateFilePath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return commandState{Commands: make(map[string]string)}, nil
+			return commandState{SchemaVersion: commandStateSchemaVersion, Commands: make(map[string]string)}, nil
 		}
 
 		return commandState{}, err
@@ -326,14 +579,11 @@ func (application *Application) saveCommandState(state commandState) error {
 		state.Commands = make(map[string]string)
 	}
 
-	if err := os.MkdirAll(filepath.Dir(application.stateFilePath), 0o755); err != nil {
-		return err
-	}
-
+	state.SchemaVersion = commandStateSchemaVersion
 	content, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(application.stateFilePath, content, 0o644)
+	return datafile.WriteAtomic(application.stateFilePath, content, 0o644)
 }
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"io"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/cedaesca/alicia/internal/clock"
 	"github.com/cedaesca/alicia/internal/commands"
 	"github.com/cedaesca/alicia/internal/discord"
 )
@@ -22,8 +25,14 @@ type fakeDiscordClient struct {
 	closeCh            chan struct{}
 	interactionHandler discord.InteractionCreateHandler
 	listCommandsErr    error
-	registeredCommands []discord.SlashCommand
 	existingCommands   []discord.RegisteredSlashCommand
+
+	existingGuildCommands map[string][]discord.RegisteredSlashCommand
+	// overwrites records each bulk overwrite by guild ID, "" being global.
+	overwrites map[string][][]discord.SlashCommand
+
+	responses   []string
+	sentChoices [][]discord.AutocompleteChoice
 }
 
 func (client *fakeDiscordClient) Open() error {
@@ -54,11 +63,63 @@ func (client *fakeDiscordClient) ListGlobalCommands() ([]discord.RegisteredSlash
 }
 
 func (client *fakeDiscordClient) RegisterGlobalCommand(command discord.SlashCommand) (string, error) {
-	client.registeredCommands = append(client.registeredCommands, command)
 	return "command-id", nil
 }
 
+func (client *fakeDiscordClient) UpdateGlobalCommand(commandID string, command discord.SlashCommand) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) DeleteGlobalCommand(commandID string) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) BulkOverwriteGlobalCommands(commands []discord.SlashCommand) ([]discord.RegisteredSlashCommand, error) {
+	return client.overwrite("", commands), nil
+}
+
+func (client *fakeDiscordClient) ListGuildCommands(guildID string) ([]discord.RegisteredSlashCommand, error) {
+	return client.existingGuildCommands[guildID], nil
+}
+
+func (client *fakeDiscordClient) RegisterGuildCommand(guildID string, command discord.SlashCommand) (string, error) {
+	return "command-id", nil
+}
+
+func (client *fakeDiscordClient) UpdateGuildCommand(guildID, commandID string, command discord.SlashCommand) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) DeleteGuildCommand(guildID, commandID string) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) BulkOverwriteGuildCommands(guildID string, commands []discord.SlashCommand) ([]discord.RegisteredSlashCommand, error) {
+	return client.overwrite(guildID, commands), nil
+}
+
+func (client *fakeDiscordClient) overwrite(guildID string, commands []discord.SlashCommand) []discord.RegisteredSlashCommand {
+	if client.overwrites == nil {
+		client.overwrites = make(map[string][][]discord.SlashCommand)
+	}
+
+	client.overwrites[guildID] = append(client.overwrites[guildID], commands)
+
+	registered := make([]discord.RegisteredSlashCommand, 0, len(commands))
+	for _, command := range commands {
+		registered = append(registered, discord.RegisteredSlashCommand{ID: guildID + "-" + command.Name, SlashCommand: command})
+	}
+
+	return registered
+}
+
 func (client *fakeDiscordClient) RespondToInteraction(interaction discord.Interaction, content string) error {
+	client.responses = append(client.responses, content)
+	return nil
+}
+
+func (client *fakeDiscordClient) RespondWithAutocompleteChoices(interaction discord.Interaction, choices []discord.AutocompleteChoice) error {
+	client.sentChoices = append(client.sentChoices, choices)
 	return nil
 }
 
@@ -74,24 +135,164 @@ func (command *staticCommand) Execute(ctx context.Context, interaction discord.I
 	return "ok", nil
 }
 
-func TestSyncSlashCommandsUsesLocalStateAsSourceOfTruth(t *testing.T) {
-	tempDir := t.TempDir()
+type suggestingCommand struct {
+	staticCommand
+}
+
+func (command *suggestingCommand) Autocomplete(ctx context.Context, interaction discord.Interaction) ([]discord.AutocompleteChoice, error) {
+	return []discord.AutocompleteChoice{{Name: "abc123 – Diario", Value: "abc123"}}, nil
+}
+
+func TestCommandHandlerAnswersAutocompleteInteractions(t *testing.T) {
+	fakeClient := &fakeDiscordClient{}
+	application := &Application{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: fakeClient,
+		commands: map[string]commands.Command{
+			"delete": &suggestingCommand{},
+			"ping":   &staticCommand{},
+		},
+	}
+
+	application.registerCommandHandler()
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "delete", Autocomplete: true, FocusedOption: "id"})
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "ping", Autocomplete: true})
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "ping"})
+
+	if len(fakeClient.sentChoices) != 2 || len(fakeClient.sentChoices[0]) != 1 || fakeClient.sentChoices[0][0].Value != "abc123" || len(fakeClient.sentChoices[1]) != 0 {
+		t.Fatalf("unexpected suggestions: %+v", fakeClient.sentChoices)
+	}
+
+	if !slices.Equal(fakeClient.responses, []string{"ok"}) {
+		t.Fatalf("expected only the command invocation to get a message, got %v", fakeClient.responses)
+	}
+}
+
+func TestCommandHandlerChecksMemberPermissions(t *testing.T) {
+	configStore := commands.NewJSONNotificationConfigStore(filepath.Join(t.TempDir(), "notification_config.json"), clock.System())
+	if err := configStore.SetManagerRole(context.Background(), "guild-1", "managers"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	fakeClient := &fakeDiscordClient{}
+	application := &Application{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: fakeClient,
+		commands: map[string]commands.Command{
+			"daily": &staticCommand{definition: discord.SlashCommand{Name: "daily", DefaultMemberPermissions: discord.PermissionManageServer}},
+		},
+		configStore: configStore,
+	}
+
+	application.registerCommandHandler()
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "daily", GuildID: "guild-1", MemberRoleIDs: []string{"members"}})
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "daily", GuildID: "guild-1", MemberRoleIDs: []string{"managers"}})
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "daily", GuildID: "guild-1", MemberPermissions: discord.PermissionManageServer})
+
+	expected := []string{
+		"No tienes permiso para usar este comando: necesitas el permiso Gestionar servidor o el rol <@&managers>",
+		"ok",
+		"ok",
+	}
+	if !slices.Equal(fakeClient.responses, expected) {
+		t.Fatalf("unexpected responses: %v", fakeClient.responses)
+	}
+}
+
+func TestManagerRoleMembersCanUseConfigurationCommands(t *testing.T) {
+	ctx := context.Background()
+	configStore := commands.NewJSONNotificationConfigStore(filepath.Join(t.TempDir(), "notification_config.json"), clock.System())
+	if err := configStore.SetManagerRole(ctx, "guild-1", "managers"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	id, err := configStore.AddDailyNotification(ctx, "guild-1", commands.DailyNotificationInput{BaseHour: "09:00", Title: "Diario", Message: "Hola"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	deleteCommand := commands.NewDeleteCommand(configStore)
+	managerRoleCommand := commands.NewManagerRoleCommand(configStore)
+
+	// Discord would hide a command with default member permissions from
+	// members who only have the manager role.
+	if permissions := deleteCommand.Definition().DefaultMemberPermissions; permissions != 0 {
+		t.Fatalf("expected /delete to be visible to every member, got permissions %d", permissions)
+	}
+
+	if permissions := managerRoleCommand.Definition().DefaultMemberPermissions; permissions != discord.PermissionManageServer {
+		t.Fatalf("expected /managerrole to stay behind Manage Server, got permissions %d", permissions)
+	}
+
+	fakeClient := &fakeDiscordClient{}
+	application := &Application{
+		ctx:           ctx,
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: fakeClient,
+		commands:      map[string]commands.Command{"delete": deleteCommand, "managerrole": managerRoleCommand},
+		configStore:   configStore,
+	}
+
+	application.registerCommandHandler()
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "delete", GuildID: "guild-1", MemberRoleIDs: []string{"members"}, Options: map[string]string{"id": id}})
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "delete", GuildID: "guild-1", MemberRoleIDs: []string{"managers"}, Options: map[string]string{"id": id}})
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "managerrole", GuildID: "guild-1", MemberRoleIDs: []string{"managers"}})
+
+	denied := "No tienes permiso para usar este comando: necesitas el permiso Gestionar servidor o el rol <@&managers>"
+	expected := []string{denied, "Notificación eliminada: " + id, denied}
+	if !slices.Equal(fakeClient.responses, expected) {
+		t.Fatalf("unexpected responses: %v", fakeClient.responses)
+	}
+}
 
+func TestCommandHandlerOnlySuggestsToAllowedMembers(t *testing.T) {
+	configStore := commands.NewJSONNotificationConfigStore(filepath.Join(t.TempDir(), "notification_config.json"), clock.System())
+	fakeClient := &fakeDiscordClient{}
 	application := &Application{
 		ctx:           context.Background(),
 		logger:        log.New(io.Discard, "", 0),
-		discordClient: &fakeDiscordClient{},
+		discordClient: fakeClient,
 		commands: map[string]commands.Command{
-			"setchannel": &staticCommand{definition: discord.SlashCommand{Name: "setchannel", Description: "Set channel"}},
+			"delete": &suggestingCommand{staticCommand{definition: discord.SlashCommand{Name: "delete", DefaultMemberPermissions: discord.PermissionManageServer}}},
 		},
-		stateFilePath: filepath.Join(tempDir, "discord_commands.json"),
+		configStore: configStore,
 	}
 
-	if err := os.MkdirAll(filepath.Dir(application.stateFilePath), 0o755); err != nil {
-		t.Fatalf("failed to create state dir: %v", err)
+	application.registerCommandHandler()
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "delete", GuildID: "guild-1", Autocomplete: true, FocusedOption: "id"})
+	fakeClient.interactionHandler(discord.Interaction{CommandName: "delete", GuildID: "guild-1", Autocomplete: true, FocusedOption: "id", MemberPermissions: discord.PermissionManageServer})
+
+	if len(fakeClient.sentChoices) != 2 || len(fakeClient.sentChoices[0]) != 0 || len(fakeClient.sentChoices[1]) != 1 {
+		t.Fatalf("expected suggestions only for the allowed member, got %+v", fakeClient.sentChoices)
+	}
+}
+
+func TestSyncSlashCommandsOverwritesChangedCommandsInOneCall(t *testing.T) {
+	setchannel := discord.SlashCommand{Name: "setchannel", Description: "Set channel"}
+	fakeClient := &fakeDiscordClient{
+		existingCommands: []discord.RegisteredSlashCommand{
+			{ID: "setchannel-id", SlashCommand: setchannel},
+			{ID: "ping-id", SlashCommand: discord.SlashCommand{Name: "ping", Description: "Old description"}},
+			{ID: "removed-id", SlashCommand: discord.SlashCommand{Name: "removed", Description: "No longer defined"}},
+		},
 	}
 
-	if err := os.WriteFile(application.stateFilePath, []byte(`{"commands":{"setchannel":"old-id"}}`), 0o644); err != nil {
+	application := &Application{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: fakeClient,
+		commands: map[string]commands.Command{
+			"setchannel": &staticCommand{definition: setchannel},
+			"ping":       &staticCommand{definition: discord.SlashCommand{Name: "ping", Description: "Ping"}},
+			"daily":      &staticCommand{definition: discord.SlashCommand{Name: "daily", Description: "Daily"}},
+		},
+		stateFilePath: filepath.Join(t.TempDir(), "discord_commands.json"),
+	}
+
+	// A stale local record must not stop missing commands from being declared.
+	if err := os.WriteFile(application.stateFilePath, []byte(`{"schema_version":1,"commands":{"daily":"stale-id"}}`), 0o644); err != nil {
 		t.Fatalf("failed to seed state file: %v", err)
 	}
 
@@ -99,19 +300,148 @@ func TestSyncSlashCommandsUsesLocalStateAsSourceOfTruth(t *testing.T) {
 		t.Fatalf("expected nil error, got %v", err)
 	}
 
-	fakeClient := application.discordClient.(*fakeDiscordClient)
-	if len(fakeClient.registeredCommands) != 0 {
-		t.Fatalf("expected command not to be registered, got %d", len(fakeClient.registeredCommands))
+	overwrites := fakeClient.overwrites[""]
+	if len(overwrites) != 1 || len(overwrites[0]) != 3 {
+		t.Fatalf("expected one global overwrite with every command, got %+v", overwrites)
+	}
+
+	state, err := application.loadCommandState()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := map[string]string{"setchannel": "-setchannel", "ping": "-ping", "daily": "-daily"}
+	if !maps.Equal(state.Commands, expected) {
+		t.Fatalf("expected recorded ids %v, got %v", expected, state.Commands)
+	}
+}
+
+func TestSyncSlashCommandsSkipsOverwriteWhenUpToDate(t *testing.T) {
+	ping := discord.SlashCommand{Name: "ping", Description: "Ping"}
+	fakeClient := &fakeDiscordClient{existingCommands: []discord.RegisteredSlashCommand{{ID: "ping-id", SlashCommand: ping}}}
+	application := &Application{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: fakeClient,
+		commands:      map[string]commands.Command{"ping": &staticCommand{definition: ping}},
+		stateFilePath: filepath.Join(t.TempDir(), "discord_commands.json"),
+	}
+
+	if err := application.syncSlashCommands(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(fakeClient.overwrites) != 0 {
+		t.Fatalf("expected no overwrite, got %+v", fakeClient.overwrites)
 	}
 }
 
-func (client *fakeDiscordClient) SendMessage(channelID, content string) error {
+func TestDiffSlashCommands(t *testing.T) {
+	diff := diffSlashCommands(
+		[]discord.SlashCommand{{Name: "ping", Description: "Ping"}, {Name: "daily"}, {Name: "list"}},
+		[]discord.RegisteredSlashCommand{
+			{ID: "1", SlashCommand: discord.SlashCommand{Name: "ping", Description: "Old"}},
+			{ID: "2", SlashCommand: discord.SlashCommand{Name: "list"}},
+			{ID: "3", SlashCommand: discord.SlashCommand{Name: "removed"}},
+		},
+	)
+
+	if !slices.Equal(diff.added, []string{"daily"}) || !slices.Equal(diff.changed, []string{"ping"}) || !slices.Equal(diff.removed, []string{"removed"}) {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+}
+
+func TestSyncSlashCommandsDeclaresCommandsInDevelopmentGuilds(t *testing.T) {
+	ping := discord.SlashCommand{Name: "ping", Description: "Ping"}
+	fakeClient := &fakeDiscordClient{
+		existingCommands: []discord.RegisteredSlashCommand{{ID: "global-id", SlashCommand: discord.SlashCommand{Name: "removed"}}},
+		existingGuildCommands: map[string][]discord.RegisteredSlashCommand{
+			"guild-1": {{ID: "stale-id", SlashCommand: discord.SlashCommand{Name: "removed"}}},
+		},
+	}
+
+	application := &Application{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: fakeClient,
+		commands:      map[string]commands.Command{"ping": &staticCommand{definition: ping}},
+		stateFilePath: filepath.Join(t.TempDir(), "discord_commands.json"),
+		devGuildIDs:   []string{"guild-1", "guild-2"},
+	}
+
+	if err := application.syncSlashCommands(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	for _, guildID := range application.devGuildIDs {
+		if overwrites := fakeClient.overwrites[guildID]; len(overwrites) != 1 || len(overwrites[0]) != 1 || overwrites[0][0].Name != "ping" {
+			t.Fatalf("expected ping to be declared in %s, got %+v", guildID, overwrites)
+		}
+	}
+
+	if _, ok := fakeClient.overwrites[""]; ok {
+		t.Fatalf("expected global commands to be left untouched, got %+v", fakeClient.overwrites[""])
+	}
+
+	state, err := application.loadCommandState()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if state.Guilds["guild-2"]["ping"] != "guild-2-ping" {
+		t.Fatalf("expected guild command ids to be recorded, got %+v", state.Guilds)
+	}
+}
+
+func TestSyncSlashCommandsFailsWhenListingFails(t *testing.T) {
+	expectedErr := errors.New("list failed")
+	application := &Application{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: &fakeDiscordClient{listCommandsErr: expectedErr},
+		commands:      map[string]commands.Command{},
+		stateFilePath: filepath.Join(t.TempDir(), "discord_commands.json"),
+	}
+
+	if err := application.syncSlashCommands(); !errors.Is(err, expectedErr) {
+		t.Fatalf("expected %v, got %v", expectedErr, err)
+	}
+}
+
+func TestLoadCommandStateMigratesUnversionedFile(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "discord_commands.json")
+	legacy := `{"commands":{"setchannel":"old-id"}}`
+	if err := os.WriteFile(stateFilePath, []byte(legacy), 0o644); err != nil {
+		t.Fatalf("failed to seed state file: %v", err)
+	}
+
+	application := &Application{logger: log.New(io.Discard, "", 0), stateFilePath: stateFilePath}
+	state, err := application.loadCommandState()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if state.SchemaVersion != commandStateSchemaVersion || state.Commands["setchannel"] != "old-id" {
+		t.Fatalf("unexpected migrated state: %+v", state)
+	}
+
+	backup, err := os.ReadFile(stateFilePath + ".v0.bak")
+	if err != nil || string(backup) != legacy {
+		t.Fatalf("expected backup of the unversioned file, got %q (%v)", backup, err)
+	}
+}
+
+func (client *fakeDiscordClient) SendMessage(channelID, content string, mentions discord.AllowedMentions) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) SendEmbed(channelID, content string, embed discord.Embed, mentions discord.AllowedMentions) error {
 	return nil
 }
 
 func TestNewApplication(t *testing.T) {
 	t.Run("uses background when context is nil", func(t *testing.T) {
-		application, err := NewApplication(nilContext, "test-token")
+		application, err := NewApplication(nilContext, Options{Token: "test-token"})
 		if err != nil {
 			t.Fatalf("expected nil error, got %v", err)
 		}
@@ -130,7 +460,7 @@ func TestNewApplication(t *testing.T) {
 	})
 
 	t.Run("fails when token is missing", func(t *testing.T) {
-		application, err := NewApplication(context.Background(), "   ")
+		application, err := NewApplication(context.Background(), Options{Token: "   "})
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -287,6 +617,24 @@ func TestReadGuildAndNotificationCounts(t *testing.T) {
 		}
 	})
 
+	t.Run("returns counts from the unified store file", func(t *testing.T) {
+		tempDir := t.TempDir()
+		configPath := filepath.Join(tempDir, "notification_config.json")
+
+		if err := os.WriteFile(configPath, []byte(`{"schema_version":1,"guilds":{"g1":{}},"notifications":[{"id":"a"},{"id":"b"}]}`), 0o644); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+
+		guildCount, notificationCount, err := readGuildAndNotificationCounts(configPath, filepath.Join(tempDir, "notifications.json"))
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if guildCount != 1 || notificationCount != 2 {
+			t.Fatalf("expected 1 guild and 2 notifications, got %d and %d", guildCount, notificationCount)
+		}
+	})
+
 	t.Run("returns partial counts and error when one file is invalid", func(t *testing.T) {
 		tempDir := t.TempDir()
 		configPath := filepath.Join(tempDir, "notification_config.json")
@@ -0,0 +1,141 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a manually driven clock. Its time only moves when Advance or Set is
+// called, firing every timer whose deadline has been reached.
+type Fake struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake returns a fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	fake := &Fake{now: now}
+	fake.cond = sync.NewCond(&fake.mu)
+
+	return fake
+}
+
+func (fake *Fake) Now() time.Time {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return fake.now
+}
+
+func (fake *Fake) NewTimer(d time.Duration) Timer {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	timer := &fakeTimer{clock: fake, c: make(chan time.Time, 1)}
+	fake.timers = append(fake.timers, timer)
+	timer.arm(d)
+
+	return timer
+}
+
+// Advance moves the clock forward by d.
+func (fake *Fake) Advance(d time.Duration) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	fake.now = fake.now.Add(d)
+	fake.fireDue()
+}
+
+// Set moves the clock to now, which may be in the past.
+func (fake *Fake) Set(now time.Time) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	fake.now = now
+	fake.fireDue()
+}
+
+// BlockUntilTimers waits until at least count timers are armed, which lets a
+// test know that a goroutine has finished reacting to the last tick.
+func (fake *Fake) BlockUntilTimers(count int) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	for fake.armedTimers() < count {
+		fake.cond.Wait()
+	}
+}
+
+func (fake *Fake) armedTimers() int {
+	armed := 0
+	for _, timer := range fake.timers {
+		if timer.armed {
+			armed++
+		}
+	}
+
+	return armed
+}
+
+// fireDue must be called with fake.mu held.
+func (fake *Fake) fireDue() {
+	for _, timer := range fake.timers {
+		if timer.armed && !timer.deadline.After(fake.now) {
+			timer.fire()
+		}
+	}
+}
+
+type fakeTimer struct {
+	clock    *Fake
+	c        chan time.Time
+	deadline time.Time
+	armed    bool
+}
+
+func (timer *fakeTimer) C() <-chan time.Time {
+	return timer.c
+}
+
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.mu.Lock()
+	defer timer.clock.mu.Unlock()
+
+	wasArmed := timer.armed
+	timer.armed = false
+
+	return wasArmed
+}
+
+func (timer *fakeTimer) Reset(d time.Duration) bool {
+	timer.clock.mu.Lock()
+	defer timer.clock.mu.Unlock()
+
+	wasArmed := timer.armed
+	timer.arm(d)
+
+	return wasArmed
+}
+
+// arm must be called with the clock mutex held.
+func (timer *fakeTimer) arm(d time.Duration) {
+	timer.deadline = timer.clock.now.Add(d)
+	timer.armed = true
+	if d <= 0 {
+		timer.fire()
+	}
+
+	timer.clock.cond.Broadcast()
+}
+
+// fire must be called with the clock mutex held.
+func (timer *fakeTimer) fire() {
+	timer.armed = false
+	select {
+	case timer.c <- timer.clock.now:
+	default:
+	}
+}
@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeFiresTimersWhenAdvanced(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	timer := fake.NewTimer(time.Hour)
+
+	fake.Advance(59 * time.Minute)
+	select {
+	case <-timer.C():
+		t.Fatal("expected timer not to fire before its deadline")
+	default:
+	}
+
+	fake.Advance(time.Minute)
+	select {
+	case firedAt := <-timer.C():
+		if !firedAt.Equal(start.Add(time.Hour)) {
+			t.Fatalf("expected timer to fire at %v, got %v", start.Add(time.Hour), firedAt)
+		}
+	default:
+		t.Fatal("expected timer to fire at its deadline")
+	}
+
+	if timer.Stop() {
+		t.Fatal("expected Stop to report an already fired timer")
+	}
+
+	if timer.Reset(time.Minute) {
+		t.Fatal("expected Reset to report the timer was not armed")
+	}
+
+	if !timer.Stop() {
+		t.Fatal("expected Stop to report an armed timer")
+	}
+
+	fake.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("expected stopped timer not to fire")
+	default:
+	}
+}
+
+func TestFakeBlockUntilTimers(t *testing.T) {
+	fake := NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	done := make(chan struct{})
+	go func() {
+		fake.BlockUntilTimers(1)
+		close(done)
+	}()
+
+	fake.NewTimer(time.Hour)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected BlockUntilTimers to return once a timer is armed")
+	}
+}
@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/cedaesca/alicia/internal/discord"
 )
 
 type byMinutesCommand struct {
+	managerCommand
 	configStore NotificationConfigStore
 }
 
@@ -22,7 +22,7 @@ func (command *byMinutesCommand) Definition() discord.SlashCommand {
 	return discord.SlashCommand{
 		Name:        "byminutes",
 		Description: "Crea una notificación recurrente por minutos",
-		Options: []discord.SlashCommandOption{
+		Options: append([]discord.SlashCommandOption{
 			{
 				Name:        "every_minutes",
 				Description: "Cada cuántos minutos se enviará la notificación",
@@ -31,7 +31,7 @@ func (command *byMinutesCommand) Definition() discord.SlashCommand {
 			},
 			{
 				Name:        "base_hour",
-				Description: "Hora base en UTC, formato HH:MM (24h)",
+				Description: "Hora base en la zona horaria del servidor, formato HH:MM (24h)",
 				Type:        discord.SlashCommandOptionTypeString,
 				Required:    true,
 			},
@@ -43,11 +43,11 @@ func (command *byMinutesCommand) Definition() discord.SlashCommand {
 			},
 			{
 				Name:        "message",
-				Description: "Mensaje de la notificación",
+				Description: "Mensaje de la notificación; admite marcadores como {fecha}, {rol} o {faltan:AAAA-MM-DD}",
 				Type:        discord.SlashCommandOptionTypeString,
 				Required:    true,
 			},
-		},
+		}, notificationOptions()...),
 	}
 }
 
@@ -76,24 +76,51 @@ func (command *byMinutesCommand) Execute(ctx context.Context, interaction discor
 		return "", MissingRequiredOptionError("message")
 	}
 
+	if err := validateNotificationTemplates(title, message); err != nil {
+		return "", err
+	}
+
 	everyMinutes, err := strconv.Atoi(everyMinutesRaw)
 	if err != nil || everyMinutes <= 0 {
 		return "", fmt.Errorf("el valor every_minutes debe ser un número entero mayor a 0")
 	}
 
-	if _, err := time.Parse("15:04", baseHour); err != nil {
-		return "", fmt.Errorf("el valor base_hour debe tener formato HH:MM (24h) en UTC")
+	if _, _, err := parseBaseHour(baseHour); err != nil {
+		return "", err
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	limits, err := parseNotificationLimits(interaction.Options, guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	style, err := parseNotificationStyle(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := parseNotificationTarget(interaction.Options)
+	if err != nil {
+		return "", err
 	}
 
 	id, err := command.configStore.AddByMinutesNotification(ctx, interaction.GuildID, ByMinutesNotificationInput{
-		EveryMinutes: everyMinutes,
-		BaseHour:     baseHour,
-		Title:        title,
-		Message:      message,
+		EveryMinutes:       everyMinutes,
+		BaseHour:           baseHour,
+		Title:              title,
+		Message:            message,
+		NotificationLimits: limits,
+		NotificationStyle:  style,
+		NotificationTarget: target,
 	})
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("Notificación creada correctamente (hora base en UTC). ID: %s", id), nil
+	return fmt.Sprintf("Notificación creada correctamente (hora base en %s). ID: %s", guildTimezoneName(guildConfig), id), nil
 }
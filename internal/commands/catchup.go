@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type catchUpCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewCatchUpCommand(configStore NotificationConfigStore) Command {
+	return &catchUpCommand{configStore: configStore}
+}
+
+func (command *catchUpCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "catchup",
+		Description: "Configura qué hacer con las notificaciones perdidas mientras el bot estaba apagado",
+		Options: []discord.SlashCommandOption{
+			{
+				Name:        "policy",
+				Description: "skip, fire_once o fire_all_up_to_N con N hasta 100 (por ejemplo fire_all_up_to_3)",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (command *catchUpCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	policyRaw := strings.TrimSpace(interaction.Options["policy"])
+	if policyRaw == "" {
+		return "", MissingRequiredOptionError("policy")
+	}
+
+	policy, _, err := parseCatchUpPolicy(policyRaw)
+	if err != nil {
+		return "", err
+	}
+
+	if err := command.configStore.SetCatchUpPolicy(ctx, interaction.GuildID, policy); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Política de recuperación del servidor configurada a %s", policy), nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/cedaesca/alicia/internal/clock"
 	"github.com/cedaesca/alicia/internal/discord"
 )
 
@@ -14,23 +15,53 @@ func MissingRequiredOptionError(optionName string) error {
 	return fmt.Errorf("falta opción obligatoria: %s", optionName)
 }
 
+func InvalidOptionError(optionName, expected string) error {
+	return fmt.Errorf("opción inválida: %s debe ser %s", optionName, expected)
+}
+
+func ConflictingOptionsError(optionName, otherOptionName string) error {
+	return fmt.Errorf("opciones incompatibles: usa %s o %s, no ambas", optionName, otherOptionName)
+}
+
 type Command interface {
 	Definition() discord.SlashCommand
 	Execute(ctx context.Context, interaction discord.Interaction) (string, error)
 }
 
+// AutocompleteCommand is a Command with autocomplete options, which suggests
+// values for the option the user is typing.
+type AutocompleteCommand interface {
+	Command
+	Autocomplete(ctx context.Context, interaction discord.Interaction) ([]discord.AutocompleteChoice, error)
+}
+
 type MessageSender interface {
-	SendMessage(channelID, content string) error
+	SendMessage(channelID, content string, mentions discord.AllowedMentions) error
 }
 
-func All(configStore NotificationConfigStore, messageSender MessageSender) []Command {
+func All(configStore NotificationConfigStore, messageSender MessageSender, clk clock.Clock) []Command {
 	return []Command{
 		NewPingCommand(),
 		NewSetChannelCommand(configStore, messageSender),
 		NewNotificationRoleCommand(configStore),
+		NewMentionsCommand(configStore),
+		NewManagerRoleCommand(configStore),
+		NewTimezoneCommand(configStore),
+		NewCatchUpCommand(configStore),
+		NewFormatCommand(configStore),
 		NewByMinutesCommand(configStore),
 		NewDailyCommand(configStore),
-		NewListCommand(configStore),
+		NewWeeklyCommand(configStore),
+		NewMonthlyCommand(configStore),
+		NewCronCommand(configStore),
+		NewRRuleCommand(configStore),
+		NewRemindCommand(configStore, clk),
+		NewListCommand(configStore, clk),
+		NewEditCommand(configStore),
+		NewPauseCommand(configStore, clk),
+		NewResumeCommand(configStore),
+		NewPauseAllCommand(configStore, clk),
+		NewResumeAllCommand(configStore),
 		NewDeleteCommand(configStore),
 	}
 }
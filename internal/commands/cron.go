@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type cronCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewCronCommand(configStore NotificationConfigStore) Command {
+	return &cronCommand{configStore: configStore}
+}
+
+func (command *cronCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "cron",
+		Description: "Crea una notificación con una expresión cron de 5 campos",
+		Options: append([]discord.SlashCommandOption{
+			{
+				Name:        "expression",
+				Description: "Expresión cron (minuto hora día mes día_semana), por ejemplo 0 9 * * MON-FRI",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "title",
+				Description: "Título de la notificación",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "message",
+				Description: "Mensaje de la notificación; admite marcadores como {fecha}, {rol} o {faltan:AAAA-MM-DD}",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+		}, notificationOptions()...),
+	}
+}
+
+func (command *cronCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	expression := strings.Join(strings.Fields(interaction.Options["expression"]), " ")
+	if expression == "" {
+		return "", MissingRequiredOptionError("expression")
+	}
+
+	title := strings.TrimSpace(interaction.Options["title"])
+	if title == "" {
+		return "", MissingRequiredOptionError("title")
+	}
+
+	message := strings.TrimSpace(interaction.Options["message"])
+	if message == "" {
+		return "", MissingRequiredOptionError("message")
+	}
+
+	if err := validateNotificationTemplates(title, message); err != nil {
+		return "", err
+	}
+
+	if _, err := parseCronExpression(expression); err != nil {
+		return "", err
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	limits, err := parseNotificationLimits(interaction.Options, guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	style, err := parseNotificationStyle(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := parseNotificationTarget(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := command.configStore.AddCronNotification(ctx, interaction.GuildID, CronNotificationInput{
+		CronExpression:     expression,
+		Title:              title,
+		Message:            message,
+		NotificationLimits: limits,
+		NotificationStyle:  style,
+		NotificationTarget: target,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Notificación creada correctamente (horario en %s). ID: %s", guildTimezoneName(guildConfig), id), nil
+}
@@ -0,0 +1,296 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchDays bounds how far ahead the next occurrence is searched so that
+// expressions that can never match (e.g. 30 of February) fail instead of looping.
+const cronSearchDays = 366 * 5
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var cronWeekdayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinuteField     = cronField{name: "minuto", min: 0, max: 59}
+	cronHourField       = cronField{name: "hora", min: 0, max: 23}
+	cronDayOfMonthField = cronField{name: "día del mes", min: 1, max: 31}
+	cronMonthField      = cronField{name: "mes", min: 1, max: 12, names: cronMonthNames}
+	cronDayOfWeekField  = cronField{name: "día de la semana", min: 0, max: 7, names: cronWeekdayNames}
+)
+
+type cronNthWeekday struct {
+	weekday time.Weekday
+	nth     int
+}
+
+type cronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	nthWeekdays []cronNthWeekday
+
+	daysOfMonthRestricted bool
+	daysOfWeekRestricted  bool
+}
+
+// parseCronExpression parses a standard 5-field cron expression
+// (minute hour day-of-month month day-of-week). Fields accept "*", ranges,
+// steps, lists and JAN-DEC / SUN-SAT names; the day-of-week field also accepts
+// "DAY#N" for the Nth weekday of the month (e.g. "MON#1").
+func parseCronExpression(expression string) (cronSchedule, error) {
+	normalized := strings.TrimSpace(expression)
+	if macro, ok := cronMacros[strings.ToLower(normalized)]; ok {
+		normalized = macro
+	}
+
+	fields := strings.Fields(normalized)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("expresión cron inválida: se esperaban 5 campos y se recibieron %d", len(fields))
+	}
+
+	var schedule cronSchedule
+	var err error
+
+	if schedule.minutes, err = parseCronField(fields[0], cronMinuteField); err != nil {
+		return cronSchedule{}, err
+	}
+
+	if schedule.hours, err = parseCronField(fields[1], cronHourField); err != nil {
+		return cronSchedule{}, err
+	}
+
+	if schedule.daysOfMonth, err = parseCronField(fields[2], cronDayOfMonthField); err != nil {
+		return cronSchedule{}, err
+	}
+
+	if schedule.months, err = parseCronField(fields[3], cronMonthField); err != nil {
+		return cronSchedule{}, err
+	}
+
+	if schedule.daysOfWeek, schedule.nthWeekdays, err = parseCronDayOfWeekField(fields[4]); err != nil {
+		return cronSchedule{}, err
+	}
+
+	// Sunday may be written as 0 or 7.
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1
+	}
+
+	schedule.daysOfMonthRestricted = !strings.HasPrefix(fields[2], "*")
+	schedule.daysOfWeekRestricted = !strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+func parseCronDayOfWeekField(value string) (uint64, []cronNthWeekday, error) {
+	var bits uint64
+	nthWeekdays := make([]cronNthWeekday, 0)
+
+	for _, part := range strings.Split(value, ",") {
+		weekdayPart, nthPart, hasNth := strings.Cut(part, "#")
+		if !hasNth {
+			partBits, err := parseCronField(part, cronDayOfWeekField)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			bits |= partBits
+			continue
+		}
+
+		weekday, err := parseCronValue(weekdayPart, cronDayOfWeekField)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		nth, err := strconv.Atoi(nthPart)
+		if err != nil || nth < 1 || nth > 5 {
+			return 0, nil, fmt.Errorf("expresión cron inválida: %q debe indicar una semana entre 1 y 5", part)
+		}
+
+		nthWeekdays = append(nthWeekdays, cronNthWeekday{weekday: time.Weekday(weekday % 7), nth: nth})
+	}
+
+	return bits, nthWeekdays, nil
+}
+
+func parseCronField(value string, field cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(value, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("expresión cron inválida: lista vacía en el campo %s", field.name)
+		}
+
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			parsedStep, err := strconv.Atoi(stepPart)
+			if err != nil || parsedStep <= 0 {
+				return 0, fmt.Errorf("expresión cron inválida: paso %q en el campo %s", stepPart, field.name)
+			}
+
+			step = parsedStep
+		}
+
+		start, end := field.min, field.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			startPart, endPart, _ := strings.Cut(rangePart, "-")
+
+			var err error
+			if start, err = parseCronValue(startPart, field); err != nil {
+				return 0, err
+			}
+
+			if end, err = parseCronValue(endPart, field); err != nil {
+				return 0, err
+			}
+
+			if start > end {
+				return 0, fmt.Errorf("expresión cron inválida: rango %q invertido en el campo %s", rangePart, field.name)
+			}
+		default:
+			parsed, err := parseCronValue(rangePart, field)
+			if err != nil {
+				return 0, err
+			}
+
+			start = parsed
+			if !hasStep {
+				end = parsed
+			}
+		}
+
+		for current := start; current <= end; current += step {
+			bits |= 1 << uint(current)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(value string, field cronField) (int, error) {
+	if named, ok := field.names[strings.ToUpper(value)]; ok {
+		return named, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("expresión cron inválida: valor %q en el campo %s", value, field.name)
+	}
+
+	if parsed < field.min || parsed > field.max {
+		return 0, fmt.Errorf("expresión cron inválida: %d fuera de rango en el campo %s (%d-%d)", parsed, field.name, field.min, field.max)
+	}
+
+	return parsed, nil
+}
+
+// next returns the first minute strictly after the given instant that matches
+// the schedule, evaluated on the wall clock of location.
+func (schedule cronSchedule) next(after time.Time, location *time.Location) (time.Time, bool) {
+	localAfter := after.In(location)
+	year, month, day := localAfter.Date()
+
+	for offset := 0; offset <= cronSearchDays; offset++ {
+		date := time.Date(year, month, day+offset, 0, 0, 0, 0, location)
+		if !schedule.matchesDay(date) {
+			continue
+		}
+
+		for hour := 0; hour < 24; hour++ {
+			if schedule.hours&(1<<uint(hour)) == 0 {
+				continue
+			}
+
+			for minute := 0; minute < 60; minute++ {
+				if schedule.minutes&(1<<uint(minute)) == 0 {
+					continue
+				}
+
+				candidate := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, location)
+				// Skip wall-clock times that do not exist because of a DST jump.
+				if candidate.Hour() != hour || candidate.Minute() != minute {
+					continue
+				}
+
+				if candidate.After(after) {
+					return candidate, true
+				}
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (schedule cronSchedule) matchesDay(date time.Time) bool {
+	if schedule.months&(1<<uint(date.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonthMatches := schedule.daysOfMonth&(1<<uint(date.Day())) != 0
+	dayOfWeekMatches := schedule.daysOfWeek&(1<<uint(date.Weekday())) != 0
+	for _, nthWeekday := range schedule.nthWeekdays {
+		if nthWeekday.weekday == date.Weekday() && (date.Day()-1)/7+1 == nthWeekday.nth {
+			dayOfWeekMatches = true
+		}
+	}
+
+	// Like classic cron, when both day fields are restricted either may match.
+	if schedule.daysOfMonthRestricted && schedule.daysOfWeekRestricted {
+		return dayOfMonthMatches || dayOfWeekMatches
+	}
+
+	return dayOfMonthMatches && dayOfWeekMatches
+}
+
+func calculateNextCronNotificationAt(expression, timezone string, now time.Time) (time.Time, error) {
+	schedule, err := parseCronExpression(expression)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location, err := loadNotificationLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next, ok := schedule.next(now, location)
+	if !ok {
+		return time.Time{}, fmt.Errorf("la expresión cron %q no tiene ocurrencias próximas", expression)
+	}
+
+	return next.UTC(), nil
+}
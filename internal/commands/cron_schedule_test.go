@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronExpressionRejectsInvalidExpressions(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * FOO *",
+		"* * * * MON#6",
+		"1,,2 * * * *",
+	}
+
+	for _, expression := range invalid {
+		if _, err := parseCronExpression(expression); err == nil {
+			t.Fatalf("expected error for %q, got nil", expression)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	location, err := loadNotificationLocation("America/Caracas")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	// 2026-10-17 is a Saturday.
+	after := time.Date(2026, 10, 17, 12, 0, 0, 0, location)
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   time.Time
+	}{
+		{"weekdays at nine", "0 9 * * MON-FRI", time.Date(2026, 10, 19, 9, 0, 0, 0, location)},
+		{"step minutes", "*/20 * * * *", time.Date(2026, 10, 17, 12, 20, 0, 0, location)},
+		{"list of hours", "30 8,13,18 * * *", time.Date(2026, 10, 17, 13, 30, 0, 0, location)},
+		{"month names", "0 0 1 JAN,JUL *", time.Date(2027, 1, 1, 0, 0, 0, 0, location)},
+		{"sunday as seven", "0 10 * * 7", time.Date(2026, 10, 18, 10, 0, 0, 0, location)},
+		{"first monday of the month", "0 9 * * MON#1", time.Date(2026, 11, 2, 9, 0, 0, 0, location)},
+		{"day of month or weekday", "0 9 20 * SUN", time.Date(2026, 10, 18, 9, 0, 0, 0, location)},
+		{"ranged step", "0 9-17/4 * * *", time.Date(2026, 10, 17, 13, 0, 0, 0, location)},
+		{"macro", "@monthly", time.Date(2026, 11, 1, 0, 0, 0, 0, location)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			schedule, err := parseCronExpression(testCase.expression)
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			next, ok := schedule.next(after, location)
+			if !ok {
+				t.Fatal("expected a next occurrence")
+			}
+
+			if !next.Equal(testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, next)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextWithoutOccurrences(t *testing.T) {
+	schedule, err := parseCronExpression("0 0 30 FEB *")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if _, ok := schedule.next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.UTC); ok {
+		t.Fatal("expected no occurrence for 30 of February")
+	}
+}
@@ -4,12 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/cedaesca/alicia/internal/discord"
 )
 
 type dailyCommand struct {
+	managerCommand
 	configStore NotificationConfigStore
 }
 
@@ -21,10 +21,10 @@ func (command *dailyCommand) Definition() discord.SlashCommand {
 	return discord.SlashCommand{
 		Name:        "daily",
 		Description: "Crea una notificación diaria",
-		Options: []discord.SlashCommandOption{
+		Options: append([]discord.SlashCommandOption{
 			{
 				Name:        "base_hour",
-				Description: "Hora base en UTC, formato HH:MM (24h)",
+				Description: "Hora base en la zona horaria del servidor, formato HH:MM (24h)",
 				Type:        discord.SlashCommandOptionTypeString,
 				Required:    true,
 			},
@@ -36,11 +36,11 @@ func (command *dailyCommand) Definition() discord.SlashCommand {
 			},
 			{
 				Name:        "message",
-				Description: "Mensaje de la notificación",
+				Description: "Mensaje de la notificación; admite marcadores como {fecha}, {rol} o {faltan:AAAA-MM-DD}",
 				Type:        discord.SlashCommandOptionTypeString,
 				Required:    true,
 			},
-		},
+		}, notificationOptions()...),
 	}
 }
 
@@ -64,18 +64,45 @@ func (command *dailyCommand) Execute(ctx context.Context, interaction discord.In
 		return "", MissingRequiredOptionError("message")
 	}
 
-	if _, err := time.Parse("15:04", baseHour); err != nil {
-		return "", fmt.Errorf("el valor base_hour debe tener formato HH:MM (24h) en UTC")
+	if err := validateNotificationTemplates(title, message); err != nil {
+		return "", err
+	}
+
+	if _, _, err := parseBaseHour(baseHour); err != nil {
+		return "", err
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	limits, err := parseNotificationLimits(interaction.Options, guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	style, err := parseNotificationStyle(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := parseNotificationTarget(interaction.Options)
+	if err != nil {
+		return "", err
 	}
 
 	id, err := command.configStore.AddDailyNotification(ctx, interaction.GuildID, DailyNotificationInput{
-		BaseHour: baseHour,
-		Title:    title,
-		Message:  message,
+		BaseHour:           baseHour,
+		Title:              title,
+		Message:            message,
+		NotificationLimits: limits,
+		NotificationStyle:  style,
+		NotificationTarget: target,
 	})
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("Notificación creada correctamente (hora base en UTC). ID: %s", id), nil
+	return fmt.Sprintf("Notificación creada correctamente (hora base en %s). ID: %s", guildTimezoneName(guildConfig), id), nil
 }
@@ -9,6 +9,7 @@ import (
 )
 
 type deleteCommand struct {
+	managerCommand
 	configStore NotificationConfigStore
 }
 
@@ -20,17 +21,14 @@ func (command *deleteCommand) Definition() discord.SlashCommand {
 	return discord.SlashCommand{
 		Name:        "delete",
 		Description: "Elimina una notificación por ID",
-		Options: []discord.SlashCommandOption{
-			{
-				Name:        "id",
-				Description: "ID de la notificación",
-				Type:        discord.SlashCommandOptionTypeString,
-				Required:    true,
-			},
-		},
+		Options:     []discord.SlashCommandOption{notificationIDOption()},
 	}
 }
 
+func (command *deleteCommand) Autocomplete(ctx context.Context, interaction discord.Interaction) ([]discord.AutocompleteChoice, error) {
+	return notificationIDChoices(ctx, command.configStore, interaction)
+}
+
 func (command *deleteCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
 	if interaction.GuildID == "" {
 		return "", ErrCommandOnlyInGuild
@@ -0,0 +1,328 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type editCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewEditCommand(configStore NotificationConfigStore) Command {
+	return &editCommand{configStore: configStore}
+}
+
+func (command *editCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "edit",
+		Description: "Modifica una notificación existente sin cambiar su ID",
+		Options: append([]discord.SlashCommandOption{
+			notificationIDOption(),
+			{
+				Name:        "title",
+				Description: "Nuevo título",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+			{
+				Name:        "message",
+				Description: "Nuevo mensaje",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+			{
+				Name:        "base_hour",
+				Description: "Nueva hora base, formato HH:MM (24h)",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+			{
+				Name:        "every_minutes",
+				Description: "Nuevo intervalo en minutos (solo byminutes)",
+				Type:        discord.SlashCommandOptionTypeInteger,
+			},
+			{
+				Name:        "days",
+				Description: "Nuevos días separados por comas (solo weekly)",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+			{
+				Name:        "day",
+				Description: "Nuevo día del mes, 1-31 (solo monthly)",
+				Type:        discord.SlashCommandOptionTypeInteger,
+			},
+			{
+				Name:        "week",
+				Description: "Nueva semana del mes, 1-4 o -1 para la última; requiere weekday (solo monthly)",
+				Type:        discord.SlashCommandOptionTypeInteger,
+			},
+			{
+				Name:        "weekday",
+				Description: "Nuevo día de la semana (lun, mar, mié, jue, vie, sáb, dom); requiere week (solo monthly)",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+			{
+				Name:        "expression",
+				Description: "Nueva expresión cron (solo cron)",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+			{
+				Name:        "rule",
+				Description: "Nueva regla RRULE (solo rrule)",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+			{
+				Name:        "date",
+				Description: "Nueva fecha, formato AAAA-MM-DD o DD/MM/AAAA (solo remind)",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+			{
+				Name:        "reset",
+				Description: "Vuelve a usar el destino del servidor: channel, role o mentions, separados por comas",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+		}, notificationOptions()...),
+	}
+}
+
+func (command *editCommand) Autocomplete(ctx context.Context, interaction discord.Interaction) ([]discord.AutocompleteChoice, error) {
+	return notificationIDChoices(ctx, command.configStore, interaction)
+}
+
+func (command *editCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	notificationID := strings.TrimSpace(interaction.Options["id"])
+	if notificationID == "" {
+		return "", MissingRequiredOptionError("id")
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	update, err := parseNotificationUpdate(interaction.Options, guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	if update.IsEmpty() {
+		return "", errors.New("indica al menos un valor para modificar")
+	}
+
+	notification, err := command.configStore.UpdateNotification(ctx, interaction.GuildID, notificationID, update)
+	if err != nil {
+		return "", err
+	}
+
+	location, err := loadNotificationLocation(guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	nextAt := notification.NextNotificationAt.In(location).Format("2006-01-02 15:04")
+	return fmt.Sprintf("Notificación actualizada: %s. Próxima: %s (%s)", notificationID, nextAt, guildTimezoneName(guildConfig)), nil
+}
+
+// parseNotificationUpdate reads the options given to /edit, validating each
+// new value the same way the command that creates the notification does.
+func parseNotificationUpdate(options map[string]string, timezone string) (NotificationUpdate, error) {
+	var update NotificationUpdate
+
+	if raw, ok := options["title"]; ok {
+		title := strings.TrimSpace(raw)
+		if title == "" {
+			return NotificationUpdate{}, InvalidOptionError("title", "un texto no vacío")
+		}
+
+		if err := validateNotificationTemplate("title", title); err != nil {
+			return NotificationUpdate{}, err
+		}
+
+		update.Title = &title
+	}
+
+	if raw, ok := options["message"]; ok {
+		message := strings.TrimSpace(raw)
+		if message == "" {
+			return NotificationUpdate{}, InvalidOptionError("message", "un texto no vacío")
+		}
+
+		if err := validateNotificationTemplate("message", message); err != nil {
+			return NotificationUpdate{}, err
+		}
+
+		update.Message = &message
+	}
+
+	if raw, ok := options["base_hour"]; ok {
+		baseHour := strings.TrimSpace(raw)
+		if _, _, err := parseBaseHour(baseHour); err != nil {
+			return NotificationUpdate{}, err
+		}
+
+		update.BaseHour = &baseHour
+	}
+
+	if raw, ok := options["every_minutes"]; ok {
+		everyMinutes, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || everyMinutes <= 0 {
+			return NotificationUpdate{}, errors.New("el valor every_minutes debe ser un número entero mayor a 0")
+		}
+
+		update.EveryMinutes = &everyMinutes
+	}
+
+	if raw, ok := options["days"]; ok {
+		weekdays, err := parseSpanishWeekdays(raw)
+		if err != nil {
+			return NotificationUpdate{}, err
+		}
+
+		update.Weekdays = weekdays
+	}
+
+	_, hasDay := options["day"]
+	_, hasWeek := options["week"]
+	_, hasWeekday := options["weekday"]
+	if hasDay || hasWeek || hasWeekday {
+		monthly, err := parseMonthlyOptions(options)
+		if err != nil {
+			return NotificationUpdate{}, err
+		}
+
+		update.MonthDay = &monthly.MonthDay
+		update.MonthWeek = &monthly.MonthWeek
+		update.Weekday = &monthly.Weekday
+	}
+
+	if raw, ok := options["expression"]; ok {
+		expression := strings.Join(strings.Fields(raw), " ")
+		if _, err := parseCronExpression(expression); err != nil {
+			return NotificationUpdate{}, err
+		}
+
+		update.CronExpression = &expression
+	}
+
+	if raw, ok := options["rule"]; ok {
+		rule := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(raw)), "RRULE:")
+		if _, err := parseRecurrenceRule(rule); err != nil {
+			return NotificationUpdate{}, err
+		}
+
+		update.RecurrenceRule = &rule
+	}
+
+	if raw, ok := options["date"]; ok {
+		date, err := parseReminderDate(strings.TrimSpace(raw))
+		if err != nil {
+			return NotificationUpdate{}, err
+		}
+
+		update.Date = &date
+	}
+
+	limits, err := parseNotificationLimits(options, timezone)
+	if err != nil {
+		return NotificationUpdate{}, err
+	}
+
+	if _, ok := options["starts_at"]; ok {
+		update.StartsAt = &limits.StartsAt
+	}
+
+	if _, ok := options["ends_at"]; ok {
+		update.EndsAt = &limits.EndsAt
+	}
+
+	if _, ok := options["max_occurrences"]; ok {
+		update.MaxOccurrences = &limits.MaxOccurrences
+	}
+
+	if _, ok := options["catch_up"]; ok {
+		update.CatchUpPolicy = &limits.CatchUpPolicy
+	}
+
+	target, err := parseNotificationTarget(options)
+	if err != nil {
+		return NotificationUpdate{}, err
+	}
+
+	if _, ok := options["channel"]; ok {
+		update.ChannelID = &target.ChannelID
+	}
+
+	if _, ok := options["role"]; ok {
+		update.RoleID = &target.RoleID
+	}
+
+	if _, ok := options["mentions"]; ok {
+		update.Mentions = &target.Mentions
+	}
+
+	if raw, ok := options["reset"]; ok {
+		if err := parseTargetReset(raw, options, &update); err != nil {
+			return NotificationUpdate{}, err
+		}
+	}
+
+	style, err := parseNotificationStyle(options)
+	if err != nil {
+		return NotificationUpdate{}, err
+	}
+
+	if _, ok := options["color"]; ok {
+		update.Color = &style.Color
+	}
+
+	if _, ok := options["image"]; ok {
+		update.ImageURL = &style.ImageURL
+	}
+
+	if _, ok := options["thumbnail"]; ok {
+		update.ThumbnailURL = &style.ThumbnailURL
+	}
+
+	return update, nil
+}
+
+// parseTargetReset clears the target overrides listed in raw, so the
+// notification goes back to the guild channel, role or mentions. Resetting an
+// override that is also given a new value is rejected.
+func parseTargetReset(raw string, options map[string]string, update *NotificationUpdate) error {
+	fields := strings.FieldsFunc(strings.ToLower(raw), func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 {
+		return InvalidOptionError("reset", "channel, role o mentions")
+	}
+
+	empty := ""
+	for _, field := range fields {
+		switch field {
+		case "channel":
+			update.ChannelID = &empty
+		case "role":
+			update.RoleID = &empty
+		case "mentions":
+			update.Mentions = &empty
+		default:
+			return InvalidOptionError("reset", "channel, role o mentions")
+		}
+
+		if _, ok := options[field]; ok {
+			return ConflictingOptionsError(field, "reset")
+		}
+	}
+
+	return nil
+}
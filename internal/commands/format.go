@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+const (
+	notificationFormatEmbed = "embed"
+	notificationFormatText  = "text"
+)
+
+type formatCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewFormatCommand(configStore NotificationConfigStore) Command {
+	return &formatCommand{configStore: configStore}
+}
+
+func (command *formatCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "format",
+		Description: "Configura si las notificaciones se envían como embed o como texto plano",
+		Options: []discord.SlashCommandOption{
+			{
+				Name:        "style",
+				Description: "embed o text",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (command *formatCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	style := strings.ToLower(strings.TrimSpace(interaction.Options["style"]))
+	if style == "" {
+		return "", MissingRequiredOptionError("style")
+	}
+
+	if style != notificationFormatEmbed && style != notificationFormatText {
+		return "", InvalidOptionError("style", notificationFormatEmbed+" o "+notificationFormatText)
+	}
+
+	if err := command.configStore.SetPlainText(ctx, interaction.GuildID, style == notificationFormatText); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Formato de notificaciones del servidor configurado a %s", style), nil
+}
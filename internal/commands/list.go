@@ -7,15 +7,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cedaesca/alicia/internal/clock"
 	"github.com/cedaesca/alicia/internal/discord"
 )
 
 type listCommand struct {
 	configStore NotificationConfigStore
+	clock       clock.Clock
 }
 
-func NewListCommand(configStore NotificationConfigStore) Command {
-	return &listCommand{configStore: configStore}
+func NewListCommand(configStore NotificationConfigStore, clk clock.Clock) Command {
+	return &listCommand{configStore: configStore, clock: clk}
 }
 
 func (command *listCommand) Definition() discord.SlashCommand {
@@ -39,28 +41,86 @@ func (command *listCommand) Execute(ctx context.Context, interaction discord.Int
 		return "No hay notificaciones configuradas.", nil
 	}
 
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	location, err := loadNotificationLocation(guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
 	sort.Slice(notifications, func(i, j int) bool {
 		return notifications[i].ID < notifications[j].ID
 	})
 
 	lines := make([]string, 0, len(notifications)+1)
-	lines = append(lines, "Notificaciones:")
-	now := time.Now().UTC()
+	lines = append(lines, fmt.Sprintf("Notificaciones (%s):", guildTimezoneName(guildConfig)))
+	now := command.clock.Now().UTC()
 	for _, notification := range notifications {
 		frequency := formatFrequency(notification)
+		nextAt := notification.NextNotificationAt.In(location).Format("2006-01-02 15:04")
 		timeUntil := formatTimeUntilNotification(notification.NextNotificationAt, now)
-		lines = append(lines, fmt.Sprintf("- **(%s) - %s** | Próxima en: %s | Frecuencia: %s", notification.ID, notification.Title, timeUntil, frequency))
+		line := fmt.Sprintf("- **(%s) - %s** | Próxima: %s (en %s) | Frecuencia: %s", notification.ID, notification.Title, nextAt, timeUntil, frequency)
+		switch {
+		case !notification.IsPaused(now):
+		case notification.ResumeAt.IsZero():
+			line = fmt.Sprintf("- **(%s) - %s** | Pausada | Frecuencia: %s", notification.ID, notification.Title, frequency)
+		default:
+			line += fmt.Sprintf(" | Pausada hasta: %s", notification.ResumeAt.In(location).Format("2006-01-02 15:04"))
+		}
+
+		if target := describeNotificationTarget(notification.EffectiveTarget(guildConfig)); target != "" {
+			line += " | " + target
+		}
+
+		if limits := describeNotificationLimits(notification, location); limits != "" {
+			line += " | " + limits
+		}
+
+		lines = append(lines, line)
 	}
 
 	return strings.Join(lines, "\n"), nil
 }
 
-func formatFrequency(notification ScheduledNotification) string {
-	if notification.Type == "daily" {
-		return "diaria"
+// describeNotificationTarget shows the channel, role and mentions a
+// notification is delivered to.
+func describeNotificationTarget(target NotificationTarget) string {
+	parts := make([]string, 0, 3)
+	if target.ChannelID != "" {
+		parts = append(parts, fmt.Sprintf("Canal: <#%s>", target.ChannelID))
 	}
 
-	return fmt.Sprintf("cada %d min", notification.EveryMinutes)
+	if target.RoleID != "" {
+		parts = append(parts, fmt.Sprintf("Rol: <@&%s>", target.RoleID))
+	}
+
+	if target.Mentions != "" {
+		parts = append(parts, "Menciones: "+target.Mentions)
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+func formatFrequency(notification ScheduledNotification) string {
+	switch notification.Type {
+	case "daily":
+		return fmt.Sprintf("diaria a las %s", notification.BaseHour)
+	case "cron":
+		return fmt.Sprintf("cron `%s`", notification.CronExpression)
+	case "weekly":
+		return fmt.Sprintf("semanal (%s) a las %s", formatSpanishWeekdays(notification.Weekdays), notification.BaseHour)
+	case "monthly":
+		return fmt.Sprintf("mensual (%s) a las %s", describeMonthlySchedule(notification.MonthDay, notification.MonthWeek, notification.Weekdays), notification.BaseHour)
+	case "once":
+		return "una sola vez"
+	case "rrule":
+		return fmt.Sprintf("rrule `%s` a las %s", notification.RecurrenceRule, notification.BaseHour)
+	default:
+		return fmt.Sprintf("cada %d min", notification.EveryMinutes)
+	}
 }
 
 func formatTimeUntilNotification(nextNotificationAt time.Time, now time.Time) string {
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type managerRoleCommand struct {
+	configStore NotificationConfigStore
+}
+
+func NewManagerRoleCommand(configStore NotificationConfigStore) Command {
+	return &managerRoleCommand{configStore: configStore}
+}
+
+func (command *managerRoleCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:                     "managerrole",
+		Description:              "Configura el rol que puede administrar Alicia sin el permiso Gestionar servidor",
+		DefaultMemberPermissions: managerPermissions,
+		Options: []discord.SlashCommandOption{
+			{
+				Name:        "role",
+				Description: "Rol administrador de Alicia; vacío para quitarlo",
+				Type:        discord.SlashCommandOptionTypeRole,
+			},
+		},
+	}
+}
+
+func (command *managerRoleCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	roleID := interaction.Options["role"]
+	if err := command.configStore.SetManagerRole(ctx, interaction.GuildID, roleID); err != nil {
+		return "", err
+	}
+
+	if roleID == "" {
+		return "Rol administrador de Alicia eliminado", nil
+	}
+
+	return fmt.Sprintf("Rol administrador de Alicia configurado a <@&%s>", roleID), nil
+}
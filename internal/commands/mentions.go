@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type mentionsCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewMentionsCommand(configStore NotificationConfigStore) Command {
+	return &mentionsCommand{configStore: configStore}
+}
+
+func (command *mentionsCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "mentions",
+		Description: "Configura las menciones de las notificaciones del servidor, además del rol",
+		Options: []discord.SlashCommandOption{
+			{
+				Name:        "mentions",
+				Description: "Roles, usuarios, @here o @everyone; vacío para no mencionar a nadie más",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+		},
+	}
+}
+
+func (command *mentionsCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	mentions, err := parseMentionList(interaction.Options["mentions"])
+	if err != nil {
+		return "", err
+	}
+
+	if err := command.configStore.SetMentions(ctx, interaction.GuildID, mentions); err != nil {
+		return "", err
+	}
+
+	if mentions == "" {
+		return "Menciones del servidor eliminadas", nil
+	}
+
+	return fmt.Sprintf("Menciones del servidor configuradas: %s", mentions), nil
+}
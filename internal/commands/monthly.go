@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type monthlyCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewMonthlyCommand(configStore NotificationConfigStore) Command {
+	return &monthlyCommand{configStore: configStore}
+}
+
+func (command *monthlyCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "monthly",
+		Description: "Crea una notificación mensual por día del mes o por día de la semana",
+		Options: append([]discord.SlashCommandOption{
+			{
+				Name:        "base_hour",
+				Description: "Hora base en la zona horaria del servidor, formato HH:MM (24h)",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "title",
+				Description: "Título de la notificación",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "message",
+				Description: "Mensaje de la notificación; admite marcadores como {fecha}, {rol} o {faltan:AAAA-MM-DD}",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "day",
+				Description: "Día del mes (1-31); en meses cortos se usa el último día",
+				Type:        discord.SlashCommandOptionTypeInteger,
+			},
+			{
+				Name:        "week",
+				Description: "Semana del mes (1-4, o -1 para la última); requiere weekday",
+				Type:        discord.SlashCommandOptionTypeInteger,
+			},
+			{
+				Name:        "weekday",
+				Description: "Día de la semana (lun, mar, mié, jue, vie, sáb, dom); requiere week",
+				Type:        discord.SlashCommandOptionTypeString,
+			},
+		}, notificationOptions()...),
+	}
+}
+
+func (command *monthlyCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	baseHour := interaction.Options["base_hour"]
+	if baseHour == "" {
+		return "", MissingRequiredOptionError("base_hour")
+	}
+
+	title := strings.TrimSpace(interaction.Options["title"])
+	if title == "" {
+		return "", MissingRequiredOptionError("title")
+	}
+
+	message := strings.TrimSpace(interaction.Options["message"])
+	if message == "" {
+		return "", MissingRequiredOptionError("message")
+	}
+
+	if err := validateNotificationTemplates(title, message); err != nil {
+		return "", err
+	}
+
+	if _, _, err := parseBaseHour(baseHour); err != nil {
+		return "", err
+	}
+
+	input, err := parseMonthlyOptions(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	input.BaseHour = baseHour
+	input.Title = title
+	input.Message = message
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	input.NotificationLimits, err = parseNotificationLimits(interaction.Options, guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	input.NotificationStyle, err = parseNotificationStyle(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	input.NotificationTarget, err = parseNotificationTarget(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := command.configStore.AddMonthlyNotification(ctx, interaction.GuildID, input)
+	if err != nil {
+		return "", err
+	}
+
+	description := describeMonthlySchedule(input.MonthDay, input.MonthWeek, []time.Weekday{input.Weekday})
+	return fmt.Sprintf("Notificación creada correctamente (mensual, %s, hora base en %s). ID: %s", description, guildTimezoneName(guildConfig), id), nil
+}
+
+func parseMonthlyOptions(options map[string]string) (MonthlyNotificationInput, error) {
+	dayRaw := strings.TrimSpace(options["day"])
+	weekRaw := strings.TrimSpace(options["week"])
+	weekdayRaw := strings.ToLower(strings.TrimSpace(options["weekday"]))
+
+	if dayRaw != "" {
+		if weekRaw != "" || weekdayRaw != "" {
+			return MonthlyNotificationInput{}, ConflictingOptionsError("day", "week/weekday")
+		}
+
+		day, err := strconv.Atoi(dayRaw)
+		if err != nil || day < 1 || day > 31 {
+			return MonthlyNotificationInput{}, InvalidOptionError("day", "un número entre 1 y 31")
+		}
+
+		return MonthlyNotificationInput{MonthDay: day}, nil
+	}
+
+	if weekRaw == "" && weekdayRaw == "" {
+		return MonthlyNotificationInput{}, MissingRequiredOptionError("day")
+	}
+
+	if weekRaw == "" {
+		return MonthlyNotificationInput{}, MissingRequiredOptionError("week")
+	}
+
+	if weekdayRaw == "" {
+		return MonthlyNotificationInput{}, MissingRequiredOptionError("weekday")
+	}
+
+	week, err := strconv.Atoi(weekRaw)
+	if err != nil || week == 0 || week < -1 || week > 4 {
+		return MonthlyNotificationInput{}, InvalidOptionError("week", "un número entre 1 y 4, o -1 para la última semana")
+	}
+
+	weekday, ok := spanishWeekdayNames[weekdayRaw]
+	if !ok {
+		return MonthlyNotificationInput{}, InvalidOptionError("weekday", "lun, mar, mié, jue, vie, sáb o dom")
+	}
+
+	return MonthlyNotificationInput{MonthWeek: week, Weekday: weekday}, nil
+}
+
+func describeMonthlySchedule(monthDay, monthWeek int, weekdays []time.Weekday) string {
+	if monthDay > 0 {
+		return fmt.Sprintf("día %d", monthDay)
+	}
+
+	if len(weekdays) == 0 {
+		return "sin día"
+	}
+
+	weekdayName := spanishWeekdayShortNames[weekdays[0]]
+	if monthWeek == -1 {
+		return fmt.Sprintf("último %s", weekdayName)
+	}
+
+	return fmt.Sprintf("%d.º %s", monthWeek, weekdayName)
+}
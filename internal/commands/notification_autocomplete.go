@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+// notificationIDOption is the option of every command that acts on an
+// existing notification, with its IDs suggested as the user types.
+func notificationIDOption() discord.SlashCommandOption {
+	return discord.SlashCommandOption{
+		Name:         "id",
+		Description:  "ID de la notificación",
+		Type:         discord.SlashCommandOptionTypeString,
+		Required:     true,
+		Autocomplete: true,
+	}
+}
+
+// notificationIDChoices suggests the guild notifications whose ID or title
+// contains the typed id value, as "ID – title" and ordered by ID.
+func notificationIDChoices(ctx context.Context, configStore NotificationConfigStore, interaction discord.Interaction) ([]discord.AutocompleteChoice, error) {
+	if interaction.GuildID == "" || interaction.FocusedOption != "id" {
+		return nil, nil
+	}
+
+	notifications, err := configStore.ListGuildNotifications(ctx, interaction.GuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].ID < notifications[j].ID
+	})
+
+	query := strings.ToLower(strings.TrimSpace(interaction.Options["id"]))
+	choices := make([]discord.AutocompleteChoice, 0, min(len(notifications), discord.MaxAutocompleteChoices))
+	for _, notification := range notifications {
+		if len(choices) == discord.MaxAutocompleteChoices {
+			break
+		}
+
+		if !strings.Contains(notification.ID, query) && !strings.Contains(strings.ToLower(notification.Title), query) {
+			continue
+		}
+
+		choices = append(choices, discord.AutocompleteChoice{
+			Name:  notification.ID + " – " + notification.Title,
+			Value: notification.ID,
+		})
+	}
+
+	return choices, nil
+}
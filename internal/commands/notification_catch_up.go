@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CatchUpSkip     = "skip"
+	CatchUpFireOnce = "fire_once"
+
+	catchUpFireAllPrefix = "fire_all_up_to_"
+)
+
+// maxCatchUpScan bounds how many occurrences are walked in one window, so a
+// one-minute notification does not scan months of history.
+const maxCatchUpScan = 10000
+
+// catchUpLookback is the first window searched for missed occurrences. It
+// doubles until the window holds enough of them or reaches the pending one.
+const catchUpLookback = time.Hour
+
+// maxCatchUpOccurrences is the largest N accepted in fire_all_up_to_N.
+const maxCatchUpOccurrences = 100
+
+// parseCatchUpPolicy validates a catch-up policy and returns its normalized
+// form together with how many missed occurrences it delivers.
+func parseCatchUpPolicy(value string) (string, int, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+
+	switch normalized {
+	case CatchUpSkip:
+		return normalized, 0, nil
+	case CatchUpFireOnce:
+		return normalized, 1, nil
+	}
+
+	if limitRaw, ok := strings.CutPrefix(normalized, catchUpFireAllPrefix); ok {
+		limit, err := strconv.Atoi(limitRaw)
+		if err == nil && limit > 0 && limit <= maxCatchUpOccurrences {
+			return normalized, limit, nil
+		}
+
+		return "", 0, InvalidOptionError("policy", fmt.Sprintf("fire_all_up_to_N con N de 1 a %d", maxCatchUpOccurrences))
+	}
+
+	return "", 0, InvalidOptionError("policy", "skip, fire_once o fire_all_up_to_N")
+}
+
+// effectiveCatchUpPolicy resolves the policy of a notification: its own
+// setting, then the guild default. Without either, recurring notifications
+// skip what was missed while one-shot reminders are still delivered late.
+func effectiveCatchUpPolicy(notification ScheduledNotification, config NotificationConfig) string {
+	if notification.CatchUpPolicy != "" {
+		return notification.CatchUpPolicy
+	}
+
+	if config.CatchUpPolicy != "" {
+		return config.CatchUpPolicy
+	}
+
+	if notification.Type == "once" {
+		return CatchUpFireOnce
+	}
+
+	return CatchUpSkip
+}
+
+// collectMissedOccurrences returns up to keep of the most recent occurrences
+// between the pending NextNotificationAt and now that were never delivered.
+// After a long downtime only the windows closest to now are walked.
+func collectMissedOccurrences(notification ScheduledNotification, now time.Time, keep int) ([]time.Time, error) {
+	if remaining := notification.RemainingOccurrences(); remaining >= 0 && keep > remaining {
+		keep = remaining
+	}
+
+	if !notification.EndsAt.IsZero() && notification.EndsAt.Before(now) {
+		now = notification.EndsAt
+	}
+
+	pending := notification.NextNotificationAt
+	if keep <= 0 || pending.IsZero() || pending.After(now) {
+		return nil, nil
+	}
+
+	span := now.Sub(pending)
+	for lookback := catchUpLookback; lookback > 0 && lookback < span; lookback *= 2 {
+		from, err := calculateNextNotificationAt(notification, now.Add(-lookback))
+		if errors.Is(err, errNoMoreOccurrences) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		missed, err := scanMissedOccurrences(notification, from, now, keep)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(missed) >= keep {
+			return missed, nil
+		}
+	}
+
+	return scanMissedOccurrences(notification, pending, now, keep)
+}
+
+// scanMissedOccurrences walks the occurrences from first up to now and keeps
+// the last keep that were never delivered.
+func scanMissedOccurrences(notification ScheduledNotification, first, now time.Time, keep int) ([]time.Time, error) {
+	var missed []time.Time
+	occurrence := first
+	for scanned := 0; scanned < maxCatchUpScan && !occurrence.After(now); scanned++ {
+		if occurrence.After(notification.LastSentAt) {
+			missed = append(missed, occurrence)
+			if len(missed) > keep {
+				missed = missed[1:]
+			}
+		}
+
+		notification.NextNotificationAt = occurrence
+		next, err := calculateNextNotificationAt(notification, occurrence)
+		if errors.Is(err, errNoMoreOccurrences) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		occurrence = next
+	}
+
+	return missed, nil
+}
@@ -3,17 +3,21 @@ package commands
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/cedaesca/alicia/internal/clock"
 	"github.com/cedaesca/alicia/internal/discord"
 )
 
 type fakeNotificationConfigStore struct {
 	setChannelErr   error
 	setRoleErr      error
+	setTimezoneErr  error
 	addByMinutesErr error
 	addDailyErr     error
+	addCronErr      error
 	listErr         error
 	deleteErr       error
 
@@ -21,14 +25,38 @@ type fakeNotificationConfigStore struct {
 	channelID         string
 	guildIDForRole    string
 	roleID            string
+	guildIDForZone    string
+	timezone          string
+	catchUpPolicy     string
+	plainText         bool
+	mentions          string
+	managerRoleID     string
+	guildConfig       NotificationConfig
 	byMinutesGuildID  string
 	byMinutesInput    ByMinutesNotificationInput
 	byMinutesID       string
 	dailyInput        DailyNotificationInput
 	dailyID           string
+	cronInput         CronNotificationInput
+	recurrenceInput   RecurrenceNotificationInput
+	onceInput         OnceNotificationInput
+	weeklyInput       WeeklyNotificationInput
+	monthlyInput      MonthlyNotificationInput
 	notifications     []ScheduledNotification
 	deletedGuildID    string
 	deletedID         string
+	updatedGuildID    string
+	updatedID         string
+	update            NotificationUpdate
+	updated           ScheduledNotification
+	updateErr         error
+	pausedGuildID     string
+	pausedID          string
+	resumeAt          time.Time
+	resumedGuildID    string
+	resumedID         string
+	changedCount      int
+	skippedCount      int
 }
 
 type fakeMessageSender struct {
@@ -37,7 +65,7 @@ type fakeMessageSender struct {
 	sentContent   string
 }
 
-func (sender *fakeMessageSender) SendMessage(channelID, content string) error {
+func (sender *fakeMessageSender) SendMessage(channelID, content string, mentions discord.AllowedMentions) error {
 	sender.sentChannelID = channelID
 	sender.sentContent = content
 	return sender.sendErr
@@ -55,6 +83,32 @@ func (store *fakeNotificationConfigStore) SetRole(_ context.Context, guildID, ro
 	return store.setRoleErr
 }
 
+func (store *fakeNotificationConfigStore) SetTimezone(_ context.Context, guildID, timezone string) error {
+	store.guildIDForZone = guildID
+	store.timezone = timezone
+	return store.setTimezoneErr
+}
+
+func (store *fakeNotificationConfigStore) SetCatchUpPolicy(_ context.Context, guildID, policy string) error {
+	store.catchUpPolicy = policy
+	return nil
+}
+
+func (store *fakeNotificationConfigStore) SetPlainText(_ context.Context, guildID string, enabled bool) error {
+	store.plainText = enabled
+	return nil
+}
+
+func (store *fakeNotificationConfigStore) SetMentions(_ context.Context, guildID, mentions string) error {
+	store.mentions = mentions
+	return nil
+}
+
+func (store *fakeNotificationConfigStore) SetManagerRole(_ context.Context, guildID, roleID string) error {
+	store.managerRoleID = roleID
+	return nil
+}
+
 func (store *fakeNotificationConfigStore) AddByMinutesNotification(_ context.Context, guildID string, input ByMinutesNotificationInput) (string, error) {
 	store.byMinutesGuildID = guildID
 	store.byMinutesInput = input
@@ -83,14 +137,47 @@ func (store *fakeNotificationConfigStore) AddDailyNotification(_ context.Context
 	return store.dailyID, nil
 }
 
+func (store *fakeNotificationConfigStore) AddCronNotification(_ context.Context, guildID string, input CronNotificationInput) (string, error) {
+	store.cronInput = input
+	if store.addCronErr != nil {
+		return "", store.addCronErr
+	}
+
+	return "c0ffee", nil
+}
+
+func (store *fakeNotificationConfigStore) AddRecurrenceNotification(_ context.Context, guildID string, input RecurrenceNotificationInput) (string, error) {
+	store.recurrenceInput = input
+	return "a1b2c3", nil
+}
+
+func (store *fakeNotificationConfigStore) AddOnceNotification(_ context.Context, guildID string, input OnceNotificationInput) (string, error) {
+	store.onceInput = input
+	return "0ce0ce", nil
+}
+
+func (store *fakeNotificationConfigStore) AddWeeklyNotification(_ context.Context, guildID string, input WeeklyNotificationInput) (string, error) {
+	store.weeklyInput = input
+	return "3ee3ee", nil
+}
+
+func (store *fakeNotificationConfigStore) AddMonthlyNotification(_ context.Context, guildID string, input MonthlyNotificationInput) (string, error) {
+	store.monthlyInput = input
+	return "m0m0m0", nil
+}
+
 func (store *fakeNotificationConfigStore) GetGuildConfig(_ context.Context, guildID string) (NotificationConfig, error) {
-	return NotificationConfig{}, nil
+	return store.guildConfig, nil
 }
 
 func (store *fakeNotificationConfigStore) ListDueNotifications(_ context.Context, now time.Time) ([]ScheduledNotification, error) {
 	return nil, nil
 }
 
+func (store *fakeNotificationConfigStore) ListNotifications(_ context.Context) ([]ScheduledNotification, error) {
+	return store.notifications, store.listErr
+}
+
 func (store *fakeNotificationConfigStore) ListGuildNotifications(_ context.Context, guildID string) ([]ScheduledNotification, error) {
 	if store.listErr != nil {
 		return nil, store.listErr
@@ -99,6 +186,37 @@ func (store *fakeNotificationConfigStore) ListGuildNotifications(_ context.Conte
 	return store.notifications, nil
 }
 
+func (store *fakeNotificationConfigStore) UpdateNotification(_ context.Context, guildID, notificationID string, update NotificationUpdate) (ScheduledNotification, error) {
+	store.updatedGuildID = guildID
+	store.updatedID = notificationID
+	store.update = update
+	return store.updated, store.updateErr
+}
+
+func (store *fakeNotificationConfigStore) PauseNotification(_ context.Context, guildID, notificationID string, resumeAt time.Time) (ScheduledNotification, error) {
+	store.pausedGuildID = guildID
+	store.pausedID = notificationID
+	store.resumeAt = resumeAt
+	return store.updated, store.updateErr
+}
+
+func (store *fakeNotificationConfigStore) ResumeNotification(_ context.Context, guildID, notificationID string) (ScheduledNotification, error) {
+	store.resumedGuildID = guildID
+	store.resumedID = notificationID
+	return store.updated, store.updateErr
+}
+
+func (store *fakeNotificationConfigStore) PauseGuildNotifications(_ context.Context, guildID string, resumeAt time.Time) (int, int, error) {
+	store.pausedGuildID = guildID
+	store.resumeAt = resumeAt
+	return store.changedCount, store.skippedCount, store.updateErr
+}
+
+func (store *fakeNotificationConfigStore) ResumeGuildNotifications(_ context.Context, guildID string) (int, error) {
+	store.resumedGuildID = guildID
+	return store.changedCount, store.updateErr
+}
+
 func (store *fakeNotificationConfigStore) DeleteNotification(_ context.Context, guildID, notificationID string) error {
 	store.deletedGuildID = guildID
 	store.deletedID = notificationID
@@ -226,8 +344,265 @@ func TestNotificationRoleCommandExecute(t *testing.T) {
 	})
 }
 
+func TestTimezoneCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewTimezoneCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"timezone": "America/Caracas"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Zona horaria de notificaciones configurada a America/Caracas" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.guildIDForZone != "guild-1" || store.timezone != "America/Caracas" {
+			t.Fatalf("unexpected store payload: guild=%q timezone=%q", store.guildIDForZone, store.timezone)
+		}
+	})
+
+	t.Run("fails with unknown timezone", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewTimezoneCommand(store)
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"timezone": "Marte/Olympus"},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if store.timezone != "" {
+			t.Fatalf("expected timezone not to be persisted, got %q", store.timezone)
+		}
+	})
+}
+
+func TestCatchUpCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewCatchUpCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"policy": "FIRE_ALL_UP_TO_3"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Política de recuperación del servidor configurada a fire_all_up_to_3" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.catchUpPolicy != "fire_all_up_to_3" {
+			t.Fatalf("expected normalized policy to be stored, got %q", store.catchUpPolicy)
+		}
+	})
+
+	t.Run("fails with unknown policy", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewCatchUpCommand(store)
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"policy": "fire_all_up_to_0"},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if store.catchUpPolicy != "" {
+			t.Fatalf("expected policy not to be persisted, got %q", store.catchUpPolicy)
+		}
+	})
+}
+
+func TestFormatCommandExecute(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewFormatCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"style": "Text"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Formato de notificaciones del servidor configurado a text" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if !store.plainText {
+			t.Fatal("expected plain text to be enabled")
+		}
+	})
+
+	t.Run("embed", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{plainText: true}
+
+		if _, err := NewFormatCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"style": "embed"},
+		}); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if store.plainText {
+			t.Fatal("expected plain text to be disabled")
+		}
+	})
+
+	t.Run("fails with unknown style", func(t *testing.T) {
+		_, err := NewFormatCommand(&fakeNotificationConfigStore{}).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"style": "html"},
+		})
+		if err == nil || err.Error() != "opción inválida: style debe ser embed o text" {
+			t.Fatalf("expected invalid style error, got %v", err)
+		}
+	})
+}
+
+func TestMentionsCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+
+		response, err := NewMentionsCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"mentions": "<@&1> <@&2> <@3> @everyone <@&1>"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Menciones del servidor configuradas: <@&1> <@&2> <@3> @everyone" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.mentions != "<@&1> <@&2> <@3> @everyone" {
+			t.Fatalf("unexpected stored mentions: %q", store.mentions)
+		}
+	})
+
+	t.Run("clears without mentions", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{mentions: "@here"}
+
+		response, err := NewMentionsCommand(store).Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Menciones del servidor eliminadas" || store.mentions != "" {
+			t.Fatalf("expected mentions to be cleared, got %q (%q)", store.mentions, response)
+		}
+	})
+
+	t.Run("fails with invalid mention", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{mentions: "@here"}
+
+		_, err := NewMentionsCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"mentions": "@todos"},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if store.mentions != "@here" {
+			t.Fatalf("expected mentions not to change, got %q", store.mentions)
+		}
+	})
+}
+
+func TestManagerRoleCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+
+		response, err := NewManagerRoleCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"role": "managers"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Rol administrador de Alicia configurado a <@&managers>" || store.managerRoleID != "managers" {
+			t.Fatalf("unexpected response %q with stored role %q", response, store.managerRoleID)
+		}
+	})
+
+	t.Run("clears without role", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{managerRoleID: "managers"}
+
+		response, err := NewManagerRoleCommand(store).Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Rol administrador de Alicia eliminado" || store.managerRoleID != "" {
+			t.Fatalf("expected manager role to be cleared, got %q (%q)", store.managerRoleID, response)
+		}
+	})
+
+	t.Run("fails outside guild", func(t *testing.T) {
+		_, err := NewManagerRoleCommand(&fakeNotificationConfigStore{}).Execute(context.Background(), discord.Interaction{})
+		if !errors.Is(err, ErrCommandOnlyInGuild) {
+			t.Fatalf("expected ErrCommandOnlyInGuild, got %v", err)
+		}
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	store := &fakeNotificationConfigStore{guildConfig: NotificationConfig{ManagerRoleID: "managers"}}
+	daily := NewDailyCommand(store)
+	managerRole := NewManagerRoleCommand(store)
+	member := discord.Interaction{GuildID: "guild-1", MemberRoleIDs: []string{"members"}}
+	manager := discord.Interaction{GuildID: "guild-1", MemberRoleIDs: []string{"members", "managers"}}
+
+	allowed := []struct {
+		name        string
+		command     Command
+		interaction discord.Interaction
+	}{
+		{"open command", NewListCommand(store, clock.System()), member},
+		{"outside guild", daily, discord.Interaction{}},
+		{"manage server", daily, discord.Interaction{GuildID: "guild-1", MemberPermissions: discord.PermissionManageServer}},
+		{"administrator", managerRole, discord.Interaction{GuildID: "guild-1", MemberPermissions: discord.PermissionAdministrator}},
+		{"manager role", daily, manager},
+	}
+	for _, test := range allowed {
+		if err := Authorize(context.Background(), store, test.command, test.interaction); err != nil {
+			t.Fatalf("%s: expected nil error, got %v", test.name, err)
+		}
+	}
+
+	err := Authorize(context.Background(), store, daily, member)
+	if err == nil || err.Error() != "No tienes permiso para usar este comando: necesitas el permiso Gestionar servidor o el rol <@&managers>" {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+
+	var denied *PermissionDeniedError
+	if err := Authorize(context.Background(), store, managerRole, manager); !errors.As(err, &denied) {
+		t.Fatalf("expected the manager role not to change itself, got %v", err)
+	}
+
+	err = Authorize(context.Background(), &fakeNotificationConfigStore{}, daily, member)
+	if err == nil || err.Error() != "No tienes permiso para usar este comando: necesitas el permiso Gestionar servidor" {
+		t.Fatalf("expected permission denied without manager role, got %v", err)
+	}
+}
+
 func TestAllCommandsIncludesNotificationCommands(t *testing.T) {
-	all := All(&fakeNotificationConfigStore{}, nil)
+	all := All(&fakeNotificationConfigStore{}, nil, clock.System())
 	if len(all) < 7 {
 		t.Fatalf("expected at least 7 commands, got %d", len(all))
 	}
@@ -281,6 +656,28 @@ func TestByMinutesCommandExecute(t *testing.T) {
 		}
 	})
 
+	t.Run("fails with broken template", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewByMinutesCommand(store)
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"every_minutes": "240",
+				"base_hour":     "16:00",
+				"title":         "Recordatorio",
+				"message":       "Enviar reporte {fecha",
+			},
+		})
+		if err == nil || err.Error() != `plantilla inválida en message: falta cerrar la llave de "{fecha"; usa {{ para escribir {` {
+			t.Fatalf("expected invalid template error, got %v", err)
+		}
+
+		if store.byMinutesGuildID != "" {
+			t.Fatal("expected notification not to be stored")
+		}
+	})
+
 	t.Run("fails with invalid base hour", func(t *testing.T) {
 		command := NewByMinutesCommand(&fakeNotificationConfigStore{})
 
@@ -325,59 +722,647 @@ func TestDailyCommandExecute(t *testing.T) {
 		}
 	})
 
-	t.Run("invalid base hour", func(t *testing.T) {
-		command := NewDailyCommand(&fakeNotificationConfigStore{})
+	t.Run("with limits", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{dailyID: "d4e5f6", guildConfig: NotificationConfig{Timezone: "America/Caracas"}}
+		command := NewDailyCommand(store)
 
 		_, err := command.Execute(context.Background(), discord.Interaction{
 			GuildID: "guild-1",
 			Options: map[string]string{
-				"base_hour": "99:00",
-				"title":     "Cierre",
-				"message":   "Revisar pendientes",
+				"base_hour":       "16:00",
+				"title":           "Temporada",
+				"message":         "Evento de temporada",
+				"starts_at":       "2030-03-01 08:00",
+				"ends_at":         "2030-03-31",
+				"max_occurrences": "10",
 			},
 		})
-		if err == nil {
-			t.Fatal("expected error, got nil")
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
 		}
-	})
-}
 
-func TestListCommandExecute(t *testing.T) {
-	t.Run("returns id title next notification and frequency", func(t *testing.T) {
-		store := &fakeNotificationConfigStore{
-			notifications: []ScheduledNotification{
-				{ID: "b2", Title: "Segundo", EveryMinutes: 30, Type: "byminutes"},
-				{ID: "a1", Title: "Primero", Type: "daily"},
-			},
+		expected := NotificationLimits{
+			StartsAt:       time.Date(2030, 3, 1, 12, 0, 0, 0, time.UTC),
+			EndsAt:         time.Date(2030, 4, 1, 3, 59, 59, 999999999, time.UTC),
+			MaxOccurrences: 10,
 		}
-		command := NewListCommand(store)
+		if store.dailyInput.NotificationLimits != expected {
+			t.Fatalf("unexpected limits: %+v", store.dailyInput.NotificationLimits)
+		}
+	})
 
-		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+	t.Run("with style", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{dailyID: "d4e5f6"}
+		command := NewDailyCommand(store)
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "16:00",
+				"title":     "Cierre",
+				"message":   "Revisar pendientes",
+				"color":     "#5865f2",
+				"thumbnail": "https://example.com/thumb.png",
+			},
+		})
 		if err != nil {
 			t.Fatalf("expected nil error, got %v", err)
 		}
 
-		expected := "Notificaciones:\n- **(a1) - Primero** | Próxima notificación en: 0 horas 0 minutos 0 segundos | Frecuencia: diaria\n- **(b2) - Segundo** | Próxima notificación en: 0 horas 0 minutos 0 segundos | Frecuencia: cada 30 min"
-		if response != expected {
-			t.Fatalf("unexpected response: %q", response)
+		expected := NotificationStyle{Color: 0x5865F2, ThumbnailURL: "https://example.com/thumb.png"}
+		if store.dailyInput.NotificationStyle != expected {
+			t.Fatalf("unexpected style: %+v", store.dailyInput.NotificationStyle)
 		}
 	})
 
-	t.Run("empty list", func(t *testing.T) {
-		command := NewListCommand(&fakeNotificationConfigStore{})
+	t.Run("with target", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{dailyID: "d4e5f6"}
 
-		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		_, err := NewDailyCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "16:00",
+				"title":     "Raid",
+				"message":   "Raid esta noche",
+				"channel":   "raids",
+				"role":      "raiders",
+				"mentions":  "<@!42>, @here",
+			},
+		})
 		if err != nil {
 			t.Fatalf("expected nil error, got %v", err)
 		}
 
-		if response != "No hay notificaciones configuradas." {
-			t.Fatalf("unexpected response: %q", response)
+		if expected := (NotificationTarget{ChannelID: "raids", RoleID: "raiders", Mentions: "<@42> @here"}); store.dailyInput.NotificationTarget != expected {
+			t.Fatalf("unexpected target: %+v", store.dailyInput.NotificationTarget)
 		}
 	})
-}
 
-func TestDeleteCommandExecute(t *testing.T) {
+	t.Run("fails with invalid mentions", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+
+		_, err := NewDailyCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "16:00",
+				"title":     "Raid",
+				"message":   "Raid esta noche",
+				"mentions":  "<@&1> raiders",
+			},
+		})
+		if err == nil || err.Error() != `mención inválida: "raiders"; usa roles, usuarios, @here o @everyone` {
+			t.Fatalf("expected invalid mention error, got %v", err)
+		}
+
+		if store.dailyInput.Title != "" {
+			t.Fatal("expected notification not to be stored")
+		}
+	})
+
+	t.Run("invalid style", func(t *testing.T) {
+		for _, options := range []map[string]string{
+			{"color": "blue"},
+			{"color": "#12345"},
+			{"image": "ftp://example.com/image.png"},
+			{"thumbnail": "example.com/thumb.png"},
+		} {
+			options["base_hour"] = "16:00"
+			options["title"] = "Cierre"
+			options["message"] = "Revisar pendientes"
+			store := &fakeNotificationConfigStore{}
+
+			_, err := NewDailyCommand(store).Execute(context.Background(), discord.Interaction{GuildID: "guild-1", Options: options})
+			if err == nil {
+				t.Fatalf("expected error for options %v, got nil", options)
+			}
+
+			if store.dailyInput.Message != "" {
+				t.Fatalf("expected notification not to be stored for options %v", options)
+			}
+		}
+	})
+
+	t.Run("with template", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{dailyID: "d4e5f6"}
+
+		_, err := NewDailyCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "16:00",
+				"title":     "Cierre del {dia_semana}",
+				"message":   "{rol} faltan {faltan:2030-12-24} para las vacaciones",
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if store.dailyInput.Message != "{rol} faltan {faltan:2030-12-24} para las vacaciones" {
+			t.Fatalf("expected template to be stored unrendered, got %q", store.dailyInput.Message)
+		}
+	})
+
+	t.Run("fails with unknown placeholder", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+
+		_, err := NewDailyCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "16:00",
+				"title":     "Cierre {usuario}",
+				"message":   "Revisar pendientes",
+			},
+		})
+		if err == nil || err.Error() != "plantilla inválida en title: marcador desconocido {usuario}" {
+			t.Fatalf("expected invalid template error, got %v", err)
+		}
+
+		if store.dailyInput.Title != "" {
+			t.Fatal("expected notification not to be stored")
+		}
+	})
+
+	t.Run("invalid max occurrences", func(t *testing.T) {
+		command := NewDailyCommand(&fakeNotificationConfigStore{})
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour":       "16:00",
+				"title":           "Cierre",
+				"message":         "Revisar pendientes",
+				"max_occurrences": "0",
+			},
+		})
+		if err == nil || err.Error() != "opción inválida: max_occurrences debe ser un número entero mayor a 0" {
+			t.Fatalf("expected invalid max_occurrences error, got %v", err)
+		}
+	})
+
+	t.Run("invalid base hour", func(t *testing.T) {
+		command := NewDailyCommand(&fakeNotificationConfigStore{})
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "99:00",
+				"title":     "Cierre",
+				"message":   "Revisar pendientes",
+			},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestWeeklyCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewWeeklyCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "09:00",
+				"days":      "vie, Lunes,mié,lun",
+				"title":     "Standup",
+				"message":   "Reunión",
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación creada correctamente (lun, mié, vie, hora base en UTC). ID: 3ee3ee" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		expected := []time.Weekday{time.Monday, time.Wednesday, time.Friday}
+		if len(store.weeklyInput.Weekdays) != len(expected) {
+			t.Fatalf("unexpected weekdays: %v", store.weeklyInput.Weekdays)
+		}
+
+		for index, weekday := range expected {
+			if store.weeklyInput.Weekdays[index] != weekday {
+				t.Fatalf("unexpected weekdays: %v", store.weeklyInput.Weekdays)
+			}
+		}
+	})
+
+	t.Run("fails with unknown day", func(t *testing.T) {
+		command := NewWeeklyCommand(&fakeNotificationConfigStore{})
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "09:00",
+				"days":      "lun,feriado",
+				"title":     "Standup",
+				"message":   "Reunión",
+			},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestMonthlyCommandExecute(t *testing.T) {
+	t.Run("by day of month", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewMonthlyCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "10:00",
+				"day":       "31",
+				"title":     "Cierre",
+				"message":   "Cierre de mes",
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación creada correctamente (mensual, día 31, hora base en UTC). ID: m0m0m0" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.monthlyInput.MonthDay != 31 || store.monthlyInput.MonthWeek != 0 || store.monthlyInput.BaseHour != "10:00" {
+			t.Fatalf("unexpected monthly payload: %+v", store.monthlyInput)
+		}
+	})
+
+	t.Run("by nth weekday", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewMonthlyCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"base_hour": "10:00",
+				"week":      "2",
+				"weekday":   "sábado",
+				"title":     "Torneo",
+				"message":   "Torneo mensual",
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación creada correctamente (mensual, 2.º sáb, hora base en UTC). ID: m0m0m0" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.monthlyInput.MonthWeek != 2 || store.monthlyInput.Weekday != time.Saturday {
+			t.Fatalf("unexpected monthly payload: %+v", store.monthlyInput)
+		}
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		testCases := []struct {
+			options  map[string]string
+			expected string
+		}{
+			{map[string]string{}, "falta opción obligatoria: day"},
+			{map[string]string{"day": "32"}, "opción inválida: day debe ser un número entre 1 y 31"},
+			{map[string]string{"day": "3", "week": "1"}, "opciones incompatibles: usa day o week/weekday, no ambas"},
+			{map[string]string{"week": "1"}, "falta opción obligatoria: weekday"},
+			{map[string]string{"week": "5", "weekday": "lun"}, "opción inválida: week debe ser un número entre 1 y 4, o -1 para la última semana"},
+		}
+
+		for _, testCase := range testCases {
+			options := map[string]string{"base_hour": "10:00", "title": "Torneo", "message": "Torneo mensual"}
+			for key, value := range testCase.options {
+				options[key] = value
+			}
+
+			_, err := NewMonthlyCommand(&fakeNotificationConfigStore{}).Execute(context.Background(), discord.Interaction{
+				GuildID: "guild-1",
+				Options: options,
+			})
+			if err == nil || err.Error() != testCase.expected {
+				t.Fatalf("options %v: expected %q, got %v", testCase.options, testCase.expected, err)
+			}
+		}
+	})
+}
+
+func TestCronCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{guildConfig: NotificationConfig{Timezone: "America/Caracas"}}
+		command := NewCronCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"expression": " 0 9  * * MON-FRI ",
+				"title":      "Standup",
+				"message":    "Reunión diaria",
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación creada correctamente (horario en America/Caracas). ID: c0ffee" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.cronInput.CronExpression != "0 9 * * MON-FRI" || store.cronInput.Title != "Standup" || store.cronInput.Message != "Reunión diaria" {
+			t.Fatalf("unexpected cron payload: %+v", store.cronInput)
+		}
+	})
+
+	t.Run("invalid expression", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewCronCommand(store)
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"expression": "0 25 * * *",
+				"title":      "Standup",
+				"message":    "Reunión diaria",
+			},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if store.cronInput.CronExpression != "" {
+			t.Fatalf("expected store not to be called, got %+v", store.cronInput)
+		}
+	})
+}
+
+func TestRRuleCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewRRuleCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"rule":      "RRULE:freq=weekly;interval=2;byday=TU,TH;until=20270630",
+				"base_hour": "18:00",
+				"title":     "Entrenamiento",
+				"message":   "A entrenar",
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación creada correctamente (hora base en UTC). ID: a1b2c3" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.recurrenceInput.RecurrenceRule != "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20270630" || store.recurrenceInput.BaseHour != "18:00" {
+			t.Fatalf("unexpected rrule payload: %+v", store.recurrenceInput)
+		}
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		command := NewRRuleCommand(&fakeNotificationConfigStore{})
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"rule":      "FREQ=HOURLY",
+				"base_hour": "18:00",
+				"title":     "Entrenamiento",
+				"message":   "A entrenar",
+			},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestRemindCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{guildConfig: NotificationConfig{Timezone: "America/Caracas"}}
+		command := NewRemindCommand(store, clock.NewFake(time.Date(2030, 5, 10, 12, 0, 0, 0, time.UTC)))
+		date := "12/05/2030"
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"date":    date,
+				"time":    "21:00",
+				"title":   "Raid",
+				"message": "Raid esta noche",
+			},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		expectedDate := "2030-05-12"
+		if response != "Recordatorio creado para el "+expectedDate+" a las 21:00 (America/Caracas). ID: 0ce0ce" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.onceInput.Date != expectedDate || store.onceInput.BaseHour != "21:00" || store.onceInput.Title != "Raid" {
+			t.Fatalf("unexpected once payload: %+v", store.onceInput)
+		}
+	})
+
+	t.Run("fails with past date", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewRemindCommand(store, clock.NewFake(time.Date(2030, 5, 10, 22, 0, 0, 0, time.UTC)))
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"date":    "2030-05-10",
+				"time":    "21:00",
+				"title":   "Raid",
+				"message": "Raid esta noche",
+			},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if store.onceInput.Date != "" {
+			t.Fatalf("expected store not to be called, got %+v", store.onceInput)
+		}
+	})
+
+	t.Run("fails with invalid date", func(t *testing.T) {
+		command := NewRemindCommand(&fakeNotificationConfigStore{}, clock.System())
+
+		_, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{
+				"date":    "mañana",
+				"time":    "21:00",
+				"title":   "Raid",
+				"message": "Raid esta noche",
+			},
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestListCommandExecute(t *testing.T) {
+	t.Run("returns id title next notification and frequency", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{
+			notifications: []ScheduledNotification{
+				{ID: "b2", Title: "Segundo", EveryMinutes: 30, Type: "byminutes", NextNotificationAt: time.Date(2020, 1, 2, 15, 30, 0, 0, time.UTC)},
+				{ID: "a1", Title: "Primero", BaseHour: "09:00", Type: "daily", NextNotificationAt: time.Date(2020, 1, 2, 13, 0, 0, 0, time.UTC)},
+			},
+			guildConfig: NotificationConfig{Timezone: "America/Caracas"},
+		}
+		command := NewListCommand(store, clock.NewFake(time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)))
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		expected := "Notificaciones (America/Caracas):\n- **(a1) - Primero** | Próxima: 2020-01-02 09:00 (en 1 horas, 0 minutos y 0 segundos) | Frecuencia: diaria a las 09:00\n- **(b2) - Segundo** | Próxima: 2020-01-02 11:30 (en 3 horas, 30 minutos y 0 segundos) | Frecuencia: cada 30 min"
+		if response != expected {
+			t.Fatalf("unexpected response: %q", response)
+		}
+	})
+
+	t.Run("shows remaining occurrences and end date", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{
+			notifications: []ScheduledNotification{
+				{
+					ID:                 "c3",
+					Title:              "Temporada",
+					BaseHour:           "09:00",
+					Type:               "daily",
+					NotificationLimits: NotificationLimits{EndsAt: time.Date(2020, 2, 1, 3, 59, 0, 0, time.UTC), MaxOccurrences: 5},
+					OccurrencesSent:    2,
+					NextNotificationAt: time.Date(2020, 1, 2, 13, 0, 0, 0, time.UTC),
+				},
+			},
+			guildConfig: NotificationConfig{Timezone: "America/Caracas"},
+		}
+		command := NewListCommand(store, clock.System())
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		expected := "Notificaciones (America/Caracas):\n- **(c3) - Temporada** | Próxima: 2020-01-02 09:00 (en 0 horas, 0 minutos y 0 segundos) | Frecuencia: diaria a las 09:00 | Restantes: 3 | Hasta: 2020-01-31 23:59"
+		if response != expected {
+			t.Fatalf("unexpected response: %q", response)
+		}
+	})
+
+	t.Run("shows the effective channel and role", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{
+			notifications: []ScheduledNotification{
+				{ID: "a1", Title: "Primero", BaseHour: "09:00", Type: "daily", NextNotificationAt: time.Date(2020, 1, 2, 13, 0, 0, 0, time.UTC)},
+				{ID: "b2", Title: "Segundo", BaseHour: "09:00", Type: "daily", NotificationTarget: NotificationTarget{ChannelID: "raids"}, NextNotificationAt: time.Date(2020, 1, 2, 13, 0, 0, 0, time.UTC)},
+				{ID: "c3", Title: "Tercero", BaseHour: "09:00", Type: "daily", NotificationTarget: NotificationTarget{ChannelID: "raids", RoleID: "raiders", Mentions: "<@42> @here"}, NextNotificationAt: time.Date(2020, 1, 2, 13, 0, 0, 0, time.UTC)},
+			},
+			guildConfig: NotificationConfig{Timezone: "America/Caracas", ChannelID: "general", RoleID: "everyone"},
+		}
+		command := NewListCommand(store, clock.NewFake(time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)))
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		expected := "Notificaciones (America/Caracas):\n" +
+			"- **(a1) - Primero** | Próxima: 2020-01-02 09:00 (en 1 horas, 0 minutos y 0 segundos) | Frecuencia: diaria a las 09:00 | Canal: <#general> | Rol: <@&everyone>\n" +
+			"- **(b2) - Segundo** | Próxima: 2020-01-02 09:00 (en 1 horas, 0 minutos y 0 segundos) | Frecuencia: diaria a las 09:00 | Canal: <#raids> | Rol: <@&everyone>\n" +
+			"- **(c3) - Tercero** | Próxima: 2020-01-02 09:00 (en 1 horas, 0 minutos y 0 segundos) | Frecuencia: diaria a las 09:00 | Canal: <#raids> | Rol: <@&raiders> | Menciones: <@42> @here"
+		if response != expected {
+			t.Fatalf("unexpected response: %q", response)
+		}
+	})
+
+	t.Run("shows paused notifications", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{
+			notifications: []ScheduledNotification{
+				{ID: "a1", Title: "Primero", BaseHour: "09:00", Type: "daily", Paused: true, NextNotificationAt: time.Date(2020, 1, 2, 13, 0, 0, 0, time.UTC)},
+				{ID: "b2", Title: "Segundo", BaseHour: "09:00", Type: "daily", Paused: true, ResumeAt: time.Date(2020, 1, 10, 4, 0, 0, 0, time.UTC), NextNotificationAt: time.Date(2020, 1, 10, 13, 0, 0, 0, time.UTC)},
+			},
+			guildConfig: NotificationConfig{Timezone: "America/Caracas"},
+		}
+		command := NewListCommand(store, clock.NewFake(time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)))
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		expected := "Notificaciones (America/Caracas):\n- **(a1) - Primero** | Pausada | Frecuencia: diaria a las 09:00\n- **(b2) - Segundo** | Próxima: 2020-01-10 09:00 (en 193 horas, 0 minutos y 0 segundos) | Frecuencia: diaria a las 09:00 | Pausada hasta: 2020-01-10 00:00"
+		if response != expected {
+			t.Fatalf("unexpected response: %q", response)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		command := NewListCommand(&fakeNotificationConfigStore{}, clock.System())
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "No hay notificaciones configuradas." {
+			t.Fatalf("unexpected response: %q", response)
+		}
+	})
+}
+
+func TestDeleteCommandAutocomplete(t *testing.T) {
+	store := &fakeNotificationConfigStore{
+		notifications: []ScheduledNotification{
+			{ID: "b2c3d4", Title: "Reunión semanal"},
+			{ID: "a1b2c3", Title: "Agua"},
+			{ID: "ffeedd", Title: "Reporte"},
+		},
+	}
+	command := NewDeleteCommand(store).(AutocompleteCommand)
+
+	choices, err := command.Autocomplete(context.Background(), discord.Interaction{
+		GuildID:       "guild-1",
+		Autocomplete:  true,
+		FocusedOption: "id",
+		Options:       map[string]string{"id": "B2"},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := []discord.AutocompleteChoice{
+		{Name: "a1b2c3 – Agua", Value: "a1b2c3"},
+		{Name: "b2c3d4 – Reunión semanal", Value: "b2c3d4"},
+	}
+	if !slices.Equal(choices, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, choices)
+	}
+
+	choices, err = command.Autocomplete(context.Background(), discord.Interaction{
+		GuildID:       "guild-1",
+		Autocomplete:  true,
+		FocusedOption: "id",
+		Options:       map[string]string{"id": "repo"},
+	})
+	if err != nil || len(choices) != 1 || choices[0].Value != "ffeedd" {
+		t.Fatalf("expected title match ffeedd, got %+v (%v)", choices, err)
+	}
+}
+
+func TestDeleteCommandExecute(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		store := &fakeNotificationConfigStore{}
 		command := NewDeleteCommand(store)
@@ -408,3 +1393,278 @@ func TestDeleteCommandExecute(t *testing.T) {
 		}
 	})
 }
+
+func TestEditCommandExecute(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{
+			guildConfig: NotificationConfig{Timezone: "America/Caracas"},
+			updated:     ScheduledNotification{ID: "abc123", NextNotificationAt: time.Date(2024, 3, 4, 13, 0, 0, 0, time.UTC)},
+		}
+		command := NewEditCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"id": "abc123", "message": " Nuevo mensaje ", "days": "lun,vie", "max_occurrences": "5"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación actualizada: abc123. Próxima: 2024-03-04 09:00 (America/Caracas)" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.updatedGuildID != "guild-1" || store.updatedID != "abc123" {
+			t.Fatalf("unexpected update target: guild=%q id=%q", store.updatedGuildID, store.updatedID)
+		}
+
+		update := store.update
+		if update.Message == nil || *update.Message != "Nuevo mensaje" || len(update.Weekdays) != 2 || update.MaxOccurrences == nil || *update.MaxOccurrences != 5 {
+			t.Fatalf("unexpected update payload: %+v", update)
+		}
+
+		if update.Title != nil || update.BaseHour != nil || update.EndsAt != nil || update.Color != nil {
+			t.Fatalf("expected options not given to be left unchanged, got %+v", update)
+		}
+	})
+
+	t.Run("style", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{updated: ScheduledNotification{ID: "abc123"}}
+
+		_, err := NewEditCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"id": "abc123", "color": "FF0000", "image": "https://example.com/image.png"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		update := store.update
+		if update.Color == nil || *update.Color != 0xFF0000 || update.ImageURL == nil || *update.ImageURL != "https://example.com/image.png" || update.ThumbnailURL != nil {
+			t.Fatalf("unexpected update payload: %+v", update)
+		}
+	})
+
+	t.Run("target", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{updated: ScheduledNotification{ID: "abc123"}}
+
+		_, err := NewEditCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"id": "abc123", "channel": "raids"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		update := store.update
+		if update.ChannelID == nil || *update.ChannelID != "raids" || update.RoleID != nil {
+			t.Fatalf("unexpected update payload: %+v", update)
+		}
+	})
+
+	t.Run("monthly", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{updated: ScheduledNotification{ID: "abc123"}}
+
+		_, err := NewEditCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"id": "abc123", "week": "2", "weekday": "sáb"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		update := store.update
+		if update.MonthDay == nil || *update.MonthDay != 0 || update.MonthWeek == nil || *update.MonthWeek != 2 || update.Weekday == nil || *update.Weekday != time.Saturday {
+			t.Fatalf("unexpected update payload: %+v", update)
+		}
+
+		if _, err := NewEditCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"id": "abc123", "week": "2"},
+		}); err == nil {
+			t.Fatal("expected error for a week without weekday, got nil")
+		}
+	})
+
+	t.Run("resets target overrides", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{updated: ScheduledNotification{ID: "abc123"}}
+
+		_, err := NewEditCommand(store).Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"id": "abc123", "reset": "Channel, mentions"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		update := store.update
+		if update.ChannelID == nil || *update.ChannelID != "" || update.Mentions == nil || *update.Mentions != "" || update.RoleID != nil {
+			t.Fatalf("unexpected update payload: %+v", update)
+		}
+	})
+
+	t.Run("fails with invalid reset", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+
+		for _, reset := range []string{"role", "everything", " "} {
+			_, err := NewEditCommand(store).Execute(context.Background(), discord.Interaction{
+				GuildID: "guild-1",
+				Options: map[string]string{"id": "abc123", "role": "officers", "reset": reset},
+			})
+			if err == nil {
+				t.Fatalf("expected error for reset %q, got nil", reset)
+			}
+		}
+
+		if store.updatedID != "" {
+			t.Fatalf("expected store not to be called, got id %q", store.updatedID)
+		}
+	})
+
+	t.Run("fails without changes", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewEditCommand(store)
+
+		_, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1", Options: map[string]string{"id": "abc123"}})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if store.updatedID != "" {
+			t.Fatalf("expected store not to be called, got id %q", store.updatedID)
+		}
+	})
+
+	t.Run("fails with invalid base hour", func(t *testing.T) {
+		command := NewEditCommand(&fakeNotificationConfigStore{})
+
+		_, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1", Options: map[string]string{"id": "abc123", "base_hour": "25:00"}})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestPauseCommandExecute(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	t.Run("pauses until a date", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{guildConfig: NotificationConfig{Timezone: "America/Caracas"}}
+		command := NewPauseCommand(store, fakeClock)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{
+			GuildID: "guild-1",
+			Options: map[string]string{"id": "abc123", "until": "2024-03-10"},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación pausada hasta el 2024-03-10 00:00 (America/Caracas): abc123" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+
+		if store.pausedGuildID != "guild-1" || store.pausedID != "abc123" || !store.resumeAt.Equal(time.Date(2024, 3, 10, 4, 0, 0, 0, time.UTC)) {
+			t.Fatalf("unexpected pause payload: guild=%q id=%q resume=%v", store.pausedGuildID, store.pausedID, store.resumeAt)
+		}
+	})
+
+	t.Run("pauses without end", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewPauseCommand(store, fakeClock)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1", Options: map[string]string{"id": "abc123"}})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación pausada: abc123" || !store.resumeAt.IsZero() {
+			t.Fatalf("unexpected response %q with resume %v", response, store.resumeAt)
+		}
+	})
+
+	t.Run("fails with past resume date", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{}
+		command := NewPauseCommand(store, fakeClock)
+
+		_, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1", Options: map[string]string{"id": "abc123", "until": "2024-02-01"}})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if store.pausedID != "" {
+			t.Fatalf("expected store not to be called, got id %q", store.pausedID)
+		}
+	})
+
+	t.Run("pauses every guild notification", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{changedCount: 3}
+		command := NewPauseAllCommand(store, fakeClock)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificaciones pausadas: 3" || store.pausedGuildID != "guild-1" {
+			t.Fatalf("unexpected response %q for guild %q", response, store.pausedGuildID)
+		}
+	})
+
+	t.Run("reports notifications left unpaused", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{changedCount: 2, skippedCount: 1}
+		command := NewPauseAllCommand(store, fakeClock)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificaciones pausadas: 2. Sin pausar, porque no tienen ocurrencias después: 1" {
+			t.Fatalf("unexpected response %q", response)
+		}
+	})
+}
+
+func TestResumeCommandExecute(t *testing.T) {
+	t.Run("resumes a notification", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{updated: ScheduledNotification{ID: "abc123", NextNotificationAt: time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)}}
+		command := NewResumeCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1", Options: map[string]string{"id": "abc123"}})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificación reanudada: abc123. Próxima: 2024-03-02 09:00 (UTC)" || store.resumedID != "abc123" {
+			t.Fatalf("unexpected response %q for id %q", response, store.resumedID)
+		}
+	})
+
+	t.Run("reports an expired notification", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{updateErr: ErrNotificationExpired}
+
+		response, err := NewResumeCommand(store).Execute(context.Background(), discord.Interaction{GuildID: "guild-1", Options: map[string]string{"id": "abc123"}})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "La notificación abc123 ya no tenía ocurrencias futuras y se eliminó" {
+			t.Fatalf("unexpected response: %q", response)
+		}
+	})
+
+	t.Run("resumes every guild notification", func(t *testing.T) {
+		store := &fakeNotificationConfigStore{changedCount: 2}
+		command := NewResumeAllCommand(store)
+
+		response, err := command.Execute(context.Background(), discord.Interaction{GuildID: "guild-1"})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if response != "Notificaciones reanudadas: 2" || store.resumedGuildID != "guild-1" {
+			t.Fatalf("unexpected response %q for guild %q", response, store.resumedGuildID)
+		}
+	})
+}
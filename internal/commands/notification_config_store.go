@@ -6,89 +6,236 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/cedaesca/alicia/internal/clock"
+	"github.com/cedaesca/alicia/internal/datafile"
 )
 
 type NotificationConfigStore interface {
 	SetChannel(ctx context.Context, guildID, channelID string) error
 	SetRole(ctx context.Context, guildID, roleID string) error
+	SetTimezone(ctx context.Context, guildID, timezone string) error
+	SetCatchUpPolicy(ctx context.Context, guildID, policy string) error
+	SetPlainText(ctx context.Context, guildID string, enabled bool) error
+	SetMentions(ctx context.Context, guildID, mentions string) error
+	SetManagerRole(ctx context.Context, guildID, roleID string) error
 	AddByMinutesNotification(ctx context.Context, guildID string, input ByMinutesNotificationInput) (string, error)
 	AddDailyNotification(ctx context.Context, guildID string, input DailyNotificationInput) (string, error)
+	AddCronNotification(ctx context.Context, guildID string, input CronNotificationInput) (string, error)
+	AddRecurrenceNotification(ctx context.Context, guildID string, input RecurrenceNotificationInput) (string, error)
+	AddOnceNotification(ctx context.Context, guildID string, input OnceNotificationInput) (string, error)
+	AddWeeklyNotification(ctx context.Context, guildID string, input WeeklyNotificationInput) (string, error)
+	AddMonthlyNotification(ctx context.Context, guildID string, input MonthlyNotificationInput) (string, error)
 	GetGuildConfig(ctx context.Context, guildID string) (NotificationConfig, error)
 	ListGuildNotifications(ctx context.Context, guildID string) ([]ScheduledNotification, error)
+	ListNotifications(ctx context.Context) ([]ScheduledNotification, error)
+	UpdateNotification(ctx context.Context, guildID, notificationID string, update NotificationUpdate) (ScheduledNotification, error)
+	PauseNotification(ctx context.Context, guildID, notificationID string, resumeAt time.Time) (ScheduledNotification, error)
+	// ResumeNotification clears the pause of a notification. One with no
+	// occurrence left, like a reminder whose date passed during the pause, is
+	// retired and ErrNotificationExpired is returned.
+	ResumeNotification(ctx context.Context, guildID, notificationID string) (ScheduledNotification, error)
+	// PauseGuildNotifications pauses every notification of the guild and
+	// returns how many it paused and how many it left as they were because
+	// they have no occurrence after resumeAt.
+	PauseGuildNotifications(ctx context.Context, guildID string, resumeAt time.Time) (paused, skipped int, err error)
+	ResumeGuildNotifications(ctx context.Context, guildID string) (int, error)
 	DeleteNotification(ctx context.Context, guildID, notificationID string) error
 	ListDueNotifications(ctx context.Context, now time.Time) ([]ScheduledNotification, error)
 	MarkNotificationSent(ctx context.Context, notificationID string, sentAt time.Time) error
 	RecalculateAllNextNotifications(ctx context.Context, now time.Time) error
 }
 
+// NotificationLimits optionally restricts a notification to a time window and
+// a number of deliveries. Zero values mean no limit. CatchUpPolicy controls
+// how many occurrences missed during downtime are delivered late; empty means
+// the guild default.
+type NotificationLimits struct {
+	StartsAt       time.Time `json:"starts_at,omitzero"`
+	EndsAt         time.Time `json:"ends_at,omitzero"`
+	MaxOccurrences int       `json:"max_occurrences,omitempty"`
+	CatchUpPolicy  string    `json:"catch_up_policy,omitempty"`
+}
+
+// NotificationStyle is how a notification looks when delivered as an embed.
+// A zero Color keeps the Discord default; the image and thumbnail are
+// optional URLs.
+type NotificationStyle struct {
+	Color        int    `json:"color,omitempty"`
+	ImageURL     string `json:"image_url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+// NotificationTarget overrides the guild channel, role and mention list for a
+// single notification. Empty fields fall back to the guild configuration.
+// Mentions is a space-separated list as normalized by parseMentionList.
+type NotificationTarget struct {
+	ChannelID string `json:"channel_id,omitempty"`
+	RoleID    string `json:"role_id,omitempty"`
+	Mentions  string `json:"mentions,omitempty"`
+}
+
 type ByMinutesNotificationInput struct {
 	EveryMinutes int
 	BaseHour     string
 	Title        string
 	Message      string
+	NotificationLimits
+	NotificationStyle
+	NotificationTarget
 }
 
-type ByMinutesNotification struct {
-	ID           string `json:"id"`
-	EveryMinutes int    `json:"every_minutes"`
-	BaseHour     string `json:"base_hour"`
-	Title        string `json:"title"`
-	Message      string `json:"message"`
+type DailyNotificationInput struct {
+	BaseHour string
+	Title    string
+	Message  string
+	NotificationLimits
+	NotificationStyle
+	NotificationTarget
 }
 
-type DailyNotificationInput struct {
+type CronNotificationInput struct {
+	CronExpression string
+	Title          string
+	Message        string
+	NotificationLimits
+	NotificationStyle
+	NotificationTarget
+}
+
+type RecurrenceNotificationInput struct {
+	RecurrenceRule string
+	BaseHour       string
+	Title          string
+	Message        string
+	NotificationLimits
+	NotificationStyle
+	NotificationTarget
+}
+
+type OnceNotificationInput struct {
+	Date     string
+	BaseHour string
+	Title    string
+	Message  string
+	NotificationLimits
+	NotificationStyle
+	NotificationTarget
+}
+
+type WeeklyNotificationInput struct {
 	BaseHour string
+	Weekdays []time.Weekday
 	Title    string
 	Message  string
+	NotificationLimits
+	NotificationStyle
+	NotificationTarget
 }
 
-type DailyNotification struct {
-	ID       string `json:"id"`
-	BaseHour string `json:"base_hour"`
-	Title    string `json:"title"`
-	Message  string `json:"message"`
+// MonthlyNotificationInput describes either a fixed day of the month
+// (MonthDay) or the Nth weekday of the month (MonthWeek and Weekday), where a
+// MonthWeek of -1 means the last one.
+type MonthlyNotificationInput struct {
+	BaseHour  string
+	MonthDay  int
+	MonthWeek int
+	Weekday   time.Weekday
+	Title     string
+	Message   string
+	NotificationLimits
+	NotificationStyle
+	NotificationTarget
 }
 
+// ScheduledNotification is the schedule entry for every notification type.
+// Monthly notifications by weekday keep their single weekday in Weekdays.
+// Date is the day of a one-shot reminder or the first day (DTSTART) of a
+// recurrence rule series, as YYYY-MM-DD in the notification timezone. A
+// Paused notification is not delivered until it is resumed or, when set,
+// until ResumeAt.
 type ScheduledNotification struct {
-	ID                 string    `json:"id"`
-	GuildID            string    `json:"guild_id"`
-	Type               string    `json:"type"`
-	EveryMinutes       int       `json:"every_minutes"`
-	BaseHour           string    `json:"base_hour"`
-	Title              string    `json:"title"`
-	Message            string    `json:"message"`
+	ID             string         `json:"id"`
+	GuildID        string         `json:"guild_id"`
+	Type           string         `json:"type"`
+	EveryMinutes   int            `json:"every_minutes"`
+	BaseHour       string         `json:"base_hour"`
+	Timezone       string         `json:"timezone,omitempty"`
+	Weekdays       []time.Weekday `json:"weekdays,omitempty"`
+	MonthDay       int            `json:"month_day,omitempty"`
+	MonthWeek      int            `json:"month_week,omitempty"`
+	CronExpression string         `json:"cron_expression,omitempty"`
+	RecurrenceRule string         `json:"recurrence_rule,omitempty"`
+	Date           string         `json:"date,omitempty"`
+	Title          string         `json:"title"`
+	Message        string         `json:"message"`
+	NotificationLimits
+	NotificationStyle
+	NotificationTarget
+	Paused             bool      `json:"paused,omitempty"`
+	ResumeAt           time.Time `json:"resume_at,omitzero"`
+	OccurrencesSent    int       `json:"occurrences_sent,omitempty"`
+	LastSentAt         time.Time `json:"last_sent_at,omitzero"`
+	CatchUpUntil       time.Time `json:"catch_up_until,omitzero"`
 	NextNotificationAt time.Time `json:"next_notification_at"`
 }
 
-type NotificationConfig struct {
-	ChannelID              string                  `json:"channel_id,omitempty"`
-	RoleID                 string                  `json:"role_id,omitempty"`
-	ByMinutesNotifications []ByMinutesNotification `json:"by_minutes_notifications,omitempty"`
-	DailyNotifications     []DailyNotification     `json:"daily_notifications,omitempty"`
+// IsCatchingUp reports whether NextNotificationAt is a missed occurrence being
+// delivered late after downtime.
+func (notification ScheduledNotification) IsCatchingUp() bool {
+	return !notification.CatchUpUntil.IsZero()
 }
 
-type notificationConfigState struct {
-	Guilds map[string]NotificationConfig `json:"guilds"`
+// NotificationConfig holds the settings of a guild. Its notifications are
+// listed with ListGuildNotifications.
+type NotificationConfig struct {
+	ChannelID     string `json:"channel_id,omitempty"`
+	RoleID        string `json:"role_id,omitempty"`
+	Timezone      string `json:"timezone,omitempty"`
+	CatchUpPolicy string `json:"catch_up_policy,omitempty"`
+	PlainText     bool   `json:"plain_text,omitempty"`
+	Mentions      string `json:"mentions,omitempty"`
+	// ManagerRoleID lets members with this role use the configuration
+	// commands without the Manage Server permission.
+	ManagerRoleID string `json:"manager_role_id,omitempty"`
 }
 
-type notificationScheduleState struct {
-	Notifications []ScheduledNotification `json:"notifications"`
+// notificationStoreSchemaVersion is the version of the unified file layout
+// written by the JSON store. Files without a version use the legacy layout
+// split between notification_config.json and notifications.json, which
+// MigrateJSONNotificationStore merges.
+const notificationStoreSchemaVersion = 1
+
+// notificationStoreState is the on-disk layout of the JSON store. Guilds hold
+// guild settings; every notification, with its definition and schedule, lives
+// once in Notifications.
+type notificationStoreState struct {
+	SchemaVersion int                           `json:"schema_version"`
+	Guilds        map[string]NotificationConfig `json:"guilds"`
+	Notifications []ScheduledNotification       `json:"notifications"`
 }
 
 type jsonNotificationConfigStore struct {
-	configFilePath        string
-	notificationsFilePath string
-	mu                    sync.Mutex
+	filePath       string
+	legacyFilePath string
+	clock          clock.Clock
+	mu             sync.Mutex
+}
+
+func NewJSONNotificationConfigStore(filePath string, clk clock.Clock) NotificationConfigStore {
+	return newJSONNotificationConfigStore(filePath, clk)
 }
 
-func NewJSONNotificationConfigStore(filePath string) NotificationConfigStore {
+func newJSONNotificationConfigStore(filePath string, clk clock.Clock) *jsonNotificationConfigStore {
 	return &jsonNotificationConfigStore{
-		configFilePath:        filePath,
-		notificationsFilePath: filepath.Join(filepath.Dir(filePath), "notifications.json"),
+		filePath:       filePath,
+		legacyFilePath: filepath.Join(filepath.Dir(filePath), "notifications.json"),
+		clock:          clk,
 	}
 }
 
@@ -96,7 +243,7 @@ func (store *jsonNotificationConfigStore) SetChannel(_ context.Context, guildID,
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	state, err := store.loadConfigState()
+	state, err := store.loadState()
 	if err != nil {
 		return err
 	}
@@ -105,14 +252,14 @@ func (store *jsonNotificationConfigStore) SetChannel(_ context.Context, guildID,
 	config.ChannelID = channelID
 	state.Guilds[guildID] = config
 
-	return store.saveConfigState(state)
+	return store.saveState(state)
 }
 
 func (store *jsonNotificationConfigStore) SetRole(_ context.Context, guildID, roleID string) error {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	state, err := store.loadConfigState()
+	state, err := store.loadState()
 	if err != nil {
 		return err
 	}
@@ -121,118 +268,170 @@ func (store *jsonNotificationConfigStore) SetRole(_ context.Context, guildID, ro
 	config.RoleID = roleID
 	state.Guilds[guildID] = config
 
-	return store.saveConfigState(state)
+	return store.saveState(state)
 }
 
-func (store *jsonNotificationConfigStore) AddByMinutesNotification(_ context.Context, guildID string, input ByMinutesNotificationInput) (string, error) {
+func (store *jsonNotificationConfigStore) SetTimezone(_ context.Context, guildID, timezone string) error {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	configState, err := store.loadConfigState()
-	if err != nil {
-		return "", err
+	if _, err := loadNotificationLocation(timezone); err != nil {
+		return err
 	}
 
-	notificationState, err := store.loadNotificationScheduleState()
+	state, err := store.loadState()
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	config := configState.Guilds[guildID]
+	config := state.Guilds[guildID]
+	config.Timezone = timezone
+	state.Guilds[guildID] = config
 
-	id, err := generateShortID()
-	if err != nil {
-		return "", err
+	for index := range state.Notifications {
+		if state.Notifications[index].GuildID == guildID {
+			state.Notifications[index].Timezone = timezone
+		}
 	}
 
-	config.ByMinutesNotifications = append(config.ByMinutesNotifications, ByMinutesNotification{
-		ID:           id,
-		EveryMinutes: input.EveryMinutes,
-		BaseHour:     input.BaseHour,
-		Title:        input.Title,
-		Message:      input.Message,
+	retired, err := rescheduleNotifications(state.Notifications, store.clock.Now().UTC(), func(notification ScheduledNotification) bool {
+		return notification.GuildID == guildID
 	})
-
-	nextNotificationAt, err := calculateInitialNextNotificationAt(input.BaseHour, input.EveryMinutes, time.Now().UTC())
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	notificationState.Notifications = append(notificationState.Notifications, ScheduledNotification{
-		ID:                 id,
-		GuildID:            guildID,
-		Type:               "byminutes",
-		EveryMinutes:       input.EveryMinutes,
-		BaseHour:           input.BaseHour,
-		Title:              input.Title,
-		Message:            input.Message,
-		NextNotificationAt: nextNotificationAt,
-	})
+	for _, notification := range retired {
+		removeNotification(&state, notification.GuildID, notification.ID)
+	}
 
-	configState.Guilds[guildID] = config
+	return store.saveState(state)
+}
 
-	if err := store.saveConfigState(configState); err != nil {
-		return "", err
+func (store *jsonNotificationConfigStore) SetCatchUpPolicy(_ context.Context, guildID, policy string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	normalized, _, err := parseCatchUpPolicy(policy)
+	if err != nil {
+		return err
 	}
 
-	if err := store.saveNotificationScheduleState(notificationState); err != nil {
-		return "", err
+	state, err := store.loadState()
+	if err != nil {
+		return err
 	}
 
-	return id, nil
+	config := state.Guilds[guildID]
+	config.CatchUpPolicy = normalized
+	state.Guilds[guildID] = config
+
+	return store.saveState(state)
 }
 
-func (store *jsonNotificationConfigStore) AddDailyNotification(_ context.Context, guildID string, input DailyNotificationInput) (string, error) {
+func (store *jsonNotificationConfigStore) SetPlainText(_ context.Context, guildID string, enabled bool) error {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	configState, err := store.loadConfigState()
+	state, err := store.loadState()
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	notificationState, err := store.loadNotificationScheduleState()
+	config := state.Guilds[guildID]
+	config.PlainText = enabled
+	state.Guilds[guildID] = config
+
+	return store.saveState(state)
+}
+
+func (store *jsonNotificationConfigStore) SetMentions(_ context.Context, guildID, mentions string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	normalized, err := parseMentionList(mentions)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	config := configState.Guilds[guildID]
+	state, err := store.loadState()
+	if err != nil {
+		return err
+	}
 
-	id, err := generateShortID()
+	config := state.Guilds[guildID]
+	config.Mentions = normalized
+	state.Guilds[guildID] = config
+
+	return store.saveState(state)
+}
+
+func (store *jsonNotificationConfigStore) SetManagerRole(_ context.Context, guildID, roleID string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state, err := store.loadState()
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	config.DailyNotifications = append(config.DailyNotifications, DailyNotification{
-		ID:       id,
-		BaseHour: input.BaseHour,
-		Title:    input.Title,
-		Message:  input.Message,
-	})
+	config := state.Guilds[guildID]
+	config.ManagerRoleID = roleID
+	state.Guilds[guildID] = config
 
-	nextNotificationAt, err := calculateInitialDailyNextNotificationAt(input.BaseHour, time.Now().UTC())
+	return store.saveState(state)
+}
+
+func (store *jsonNotificationConfigStore) AddByMinutesNotification(_ context.Context, guildID string, input ByMinutesNotificationInput) (string, error) {
+	return store.addNotification(guildID, input.scheduledNotification())
+}
+
+func (store *jsonNotificationConfigStore) AddDailyNotification(_ context.Context, guildID string, input DailyNotificationInput) (string, error) {
+	return store.addNotification(guildID, input.scheduledNotification())
+}
+
+func (store *jsonNotificationConfigStore) AddWeeklyNotification(_ context.Context, guildID string, input WeeklyNotificationInput) (string, error) {
+	return store.addNotification(guildID, input.scheduledNotification())
+}
+
+func (store *jsonNotificationConfigStore) AddMonthlyNotification(_ context.Context, guildID string, input MonthlyNotificationInput) (string, error) {
+	return store.addNotification(guildID, input.scheduledNotification())
+}
+
+func (store *jsonNotificationConfigStore) AddCronNotification(_ context.Context, guildID string, input CronNotificationInput) (string, error) {
+	return store.addNotification(guildID, input.scheduledNotification())
+}
+
+func (store *jsonNotificationConfigStore) AddRecurrenceNotification(_ context.Context, guildID string, input RecurrenceNotificationInput) (string, error) {
+	return store.addNotification(guildID, input.scheduledNotification())
+}
+
+func (store *jsonNotificationConfigStore) AddOnceNotification(_ context.Context, guildID string, input OnceNotificationInput) (string, error) {
+	return store.addNotification(guildID, input.scheduledNotification())
+}
+
+func (store *jsonNotificationConfigStore) addNotification(guildID string, notification ScheduledNotification) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state, err := store.loadState()
 	if err != nil {
 		return "", err
 	}
 
-	notificationState.Notifications = append(notificationState.Notifications, ScheduledNotification{
-		ID:                 id,
-		GuildID:            guildID,
-		Type:               "daily",
-		EveryMinutes:       0,
-		BaseHour:           input.BaseHour,
-		Title:              input.Title,
-		Message:            input.Message,
-		NextNotificationAt: nextNotificationAt,
-	})
-
-	configState.Guilds[guildID] = config
+	id, err := generateShortID()
+	if err != nil {
+		return "", err
+	}
 
-	if err := store.saveConfigState(configState); err != nil {
+	notification, err = newScheduledNotification(notification, id, guildID, state.Guilds[guildID], store.clock.Now().UTC())
+	if err != nil {
 		return "", err
 	}
 
-	if err := store.saveNotificationScheduleState(notificationState); err != nil {
+	state.Notifications = append(state.Notifications, notification)
+
+	if err := store.saveState(state); err != nil {
 		return "", err
 	}
 
@@ -243,7 +442,7 @@ func (store *jsonNotificationConfigStore) GetGuildConfig(_ context.Context, guil
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	state, err := store.loadConfigState()
+	state, err := store.loadState()
 	if err != nil {
 		return NotificationConfig{}, err
 	}
@@ -255,7 +454,7 @@ func (store *jsonNotificationConfigStore) ListDueNotifications(_ context.Context
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	state, err := store.loadNotificationScheduleState()
+	state, err := store.loadState()
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +462,7 @@ func (store *jsonNotificationConfigStore) ListDueNotifications(_ context.Context
 	normalizedNow := now.UTC()
 	dueNotifications := make([]ScheduledNotification, 0)
 	for _, notification := range state.Notifications {
-		if !notification.NextNotificationAt.After(normalizedNow) {
+		if notification.hasOccurrencesLeft() && !notification.IsPaused(normalizedNow) && !notification.NextNotificationAt.After(normalizedNow) {
 			dueNotifications = append(dueNotifications, notification)
 		}
 	}
@@ -271,11 +470,23 @@ func (store *jsonNotificationConfigStore) ListDueNotifications(_ context.Context
 	return dueNotifications, nil
 }
 
+func (store *jsonNotificationConfigStore) ListNotifications(_ context.Context) ([]ScheduledNotification, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state, err := store.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Notifications, nil
+}
+
 func (store *jsonNotificationConfigStore) ListGuildNotifications(_ context.Context, guildID string) ([]ScheduledNotification, error) {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	state, err := store.loadNotificationScheduleState()
+	state, err := store.loadState()
 	if err != nil {
 		return nil, err
 	}
@@ -290,72 +501,160 @@ func (store *jsonNotificationConfigStore) ListGuildNotifications(_ context.Conte
 	return notifications, nil
 }
 
-func (store *jsonNotificationConfigStore) DeleteNotification(_ context.Context, guildID, notificationID string) error {
-	store.mu.Lock()
-	defer store.mu.Unlock()
+func (store *jsonNotificationConfigStore) UpdateNotification(_ context.Context, guildID, notificationID string, update NotificationUpdate) (ScheduledNotification, error) {
+	return store.changeNotification(guildID, notificationID, func(notification *ScheduledNotification, now time.Time) error {
+		updated, err := updateScheduledNotification(*notification, update, now)
+		if err != nil {
+			return err
+		}
+
+		*notification = updated
+		return nil
+	})
+}
+
+func (store *jsonNotificationConfigStore) PauseNotification(_ context.Context, guildID, notificationID string, resumeAt time.Time) (ScheduledNotification, error) {
+	return store.changeNotification(guildID, notificationID, func(notification *ScheduledNotification, now time.Time) error {
+		err := pauseNotification(notification, resumeAt, now)
+		if errors.Is(err, errNoMoreOccurrences) {
+			return errors.New("la notificación no tiene ocurrencias después de la reanudación")
+		}
 
-	configState, err := store.loadConfigState()
-	if err != nil {
 		return err
-	}
+	})
+}
 
-	notificationState, err := store.loadNotificationScheduleState()
+func (store *jsonNotificationConfigStore) ResumeNotification(_ context.Context, guildID, notificationID string) (ScheduledNotification, error) {
+	return store.changeNotification(guildID, notificationID, resumeNotification)
+}
+
+// changeNotification applies change to a copy of the guild notification and
+// saves it only when change succeeds. When change leaves it without
+// occurrences, the notification is retired and ErrNotificationExpired is
+// returned.
+func (store *jsonNotificationConfigStore) changeNotification(guildID, notificationID string, change func(notification *ScheduledNotification, now time.Time) error) (ScheduledNotification, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state, err := store.loadState()
 	if err != nil {
-		return err
+		return ScheduledNotification{}, err
 	}
 
-	deleted := false
-	filteredScheduled := make([]ScheduledNotification, 0, len(notificationState.Notifications))
-	for _, notification := range notificationState.Notifications {
-		if notification.ID == notificationID && notification.GuildID == guildID {
-			deleted = true
+	for index, notification := range state.Notifications {
+		if notification.ID != notificationID || notification.GuildID != guildID {
 			continue
 		}
 
-		filteredScheduled = append(filteredScheduled, notification)
-	}
+		err := change(&notification, store.clock.Now().UTC())
+		if errors.Is(err, errNoMoreOccurrences) {
+			removeNotification(&state, guildID, notificationID)
+			if err := store.saveState(state); err != nil {
+				return ScheduledNotification{}, err
+			}
 
-	if !deleted {
-		return errors.New("notificación no encontrada")
+			return ScheduledNotification{}, ErrNotificationExpired
+		}
+
+		if err != nil {
+			return ScheduledNotification{}, err
+		}
+
+		state.Notifications[index] = notification
+		if err := store.saveState(state); err != nil {
+			return ScheduledNotification{}, err
+		}
+
+		return notification, nil
 	}
 
-	notificationState.Notifications = filteredScheduled
+	return ScheduledNotification{}, errors.New("notificación no encontrada")
+}
 
-	config := configState.Guilds[guildID]
-	filteredConfig := make([]ByMinutesNotification, 0, len(config.ByMinutesNotifications))
-	for _, notification := range config.ByMinutesNotifications {
-		if notification.ID == notificationID {
-			continue
+func (store *jsonNotificationConfigStore) PauseGuildNotifications(_ context.Context, guildID string, resumeAt time.Time) (int, int, error) {
+	skipped := 0
+	paused, err := store.changeGuildNotifications(guildID, pauseGuildNotification(resumeAt, &skipped))
+	return paused, skipped, err
+}
+
+func (store *jsonNotificationConfigStore) ResumeGuildNotifications(_ context.Context, guildID string) (int, error) {
+	return store.changeGuildNotifications(guildID, func(notification *ScheduledNotification, now time.Time) (bool, error) {
+		if !notification.IsPaused(now) {
+			return false, nil
 		}
 
-		filteredConfig = append(filteredConfig, notification)
+		return true, resumeNotification(notification, now)
+	})
+}
+
+// changeGuildNotifications applies change to every notification of the guild
+// and returns how many it changed. Notifications left without occurrences are
+// retired, like when the guild timezone changes.
+func (store *jsonNotificationConfigStore) changeGuildNotifications(guildID string, change func(notification *ScheduledNotification, now time.Time) (bool, error)) (int, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state, err := store.loadState()
+	if err != nil {
+		return 0, err
 	}
 
-	config.ByMinutesNotifications = filteredConfig
-	filteredDailyConfig := make([]DailyNotification, 0, len(config.DailyNotifications))
-	for _, notification := range config.DailyNotifications {
-		if notification.ID == notificationID {
+	now := store.clock.Now().UTC()
+	changed := 0
+	retired := make([]ScheduledNotification, 0)
+	for index := range state.Notifications {
+		notification := &state.Notifications[index]
+		if notification.GuildID != guildID {
 			continue
 		}
 
-		filteredDailyConfig = append(filteredDailyConfig, notification)
+		ok, err := change(notification, now)
+		if errors.Is(err, errNoMoreOccurrences) {
+			retired = append(retired, *notification)
+			continue
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			changed++
+		}
 	}
 
-	config.DailyNotifications = filteredDailyConfig
-	configState.Guilds[guildID] = config
+	for _, notification := range retired {
+		removeNotification(&state, notification.GuildID, notification.ID)
+	}
 
-	if err := store.saveNotificationScheduleState(notificationState); err != nil {
+	if err := store.saveState(state); err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
+
+func (store *jsonNotificationConfigStore) DeleteNotification(_ context.Context, guildID, notificationID string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	state, err := store.loadState()
+	if err != nil {
 		return err
 	}
 
-	return store.saveConfigState(configState)
+	if !removeNotification(&state, guildID, notificationID) {
+		return errors.New("notificación no encontrada")
+	}
+
+	return store.saveState(state)
 }
 
 func (store *jsonNotificationConfigStore) MarkNotificationSent(_ context.Context, notificationID string, sentAt time.Time) error {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	state, err := store.loadNotificationScheduleState()
+	state, err := store.loadState()
 	if err != nil {
 		return err
 	}
@@ -367,13 +666,14 @@ func (store *jsonNotificationConfigStore) MarkNotificationSent(_ context.Context
 			continue
 		}
 
-		nextNotificationAt, err := calculateNextNotificationAt(*notification, normalizedSentAt)
-		if err != nil {
+		switch err := advanceAfterDelivery(notification, normalizedSentAt); {
+		case errors.Is(err, errNoMoreOccurrences):
+			removeNotification(&state, notification.GuildID, notification.ID)
+		case err != nil:
 			return err
 		}
 
-		notification.NextNotificationAt = nextNotificationAt
-		return store.saveNotificationScheduleState(state)
+		return store.saveState(state)
 	}
 
 	return errors.New("notificación no encontrada")
@@ -383,7 +683,7 @@ func (store *jsonNotificationConfigStore) RecalculateAllNextNotifications(_ cont
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	state, err := store.loadNotificationScheduleState()
+	state, err := store.loadState()
 	if err != nil {
 		return err
 	}
@@ -391,94 +691,123 @@ func (store *jsonNotificationConfigStore) RecalculateAllNextNotifications(_ cont
 	normalizedNow := now.UTC()
 	for index := range state.Notifications {
 		notification := &state.Notifications[index]
-
-		next, err := calculateNextFromBaseHour(*notification, normalizedNow)
-		if err != nil {
+		if err := startCatchUp(notification, state.Guilds[notification.GuildID], normalizedNow); err != nil {
 			return err
 		}
+	}
+
+	retired, err := rescheduleNotifications(state.Notifications, normalizedNow, func(notification ScheduledNotification) bool {
+		return !notification.IsCatchingUp()
+	})
+	if err != nil {
+		return err
+	}
 
-		notification.NextNotificationAt = next
+	for _, notification := range retired {
+		removeNotification(&state, notification.GuildID, notification.ID)
 	}
 
-	return store.saveNotificationScheduleState(state)
+	return store.saveState(state)
 }
 
-func (store *jsonNotificationConfigStore) loadConfigState() (notificationConfigState, error) {
-	content, err := os.ReadFile(store.configFilePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return notificationConfigState{Guilds: make(map[string]NotificationConfig)}, nil
+// rescheduleNotifications recomputes the next time of every notification
+// accepted by include and returns the ones that will never fire again.
+func rescheduleNotifications(notifications []ScheduledNotification, now time.Time, include func(ScheduledNotification) bool) ([]ScheduledNotification, error) {
+	retired := make([]ScheduledNotification, 0)
+	for index := range notifications {
+		notification := &notifications[index]
+		if !include(*notification) {
+			continue
+		}
+
+		err := rescheduleNotification(notification, now)
+		if errors.Is(err, errNoMoreOccurrences) {
+			retired = append(retired, *notification)
+			continue
 		}
 
-		return notificationConfigState{}, err
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	var state notificationConfigState
-	if err := json.Unmarshal(content, &state); err != nil {
-		return notificationConfigState{}, err
+	return retired, nil
+}
+
+func removeNotification(state *notificationStoreState, guildID, notificationID string) bool {
+	filtered := make([]ScheduledNotification, 0, len(state.Notifications))
+	for _, notification := range state.Notifications {
+		if notification.ID == notificationID && notification.GuildID == guildID {
+			continue
+		}
+
+		filtered = append(filtered, notification)
 	}
 
-	if state.Guilds == nil {
-		state.Guilds = make(map[string]NotificationConfig)
+	if len(filtered) == len(state.Notifications) {
+		return false
 	}
 
-	return state, nil
+	state.Notifications = filtered
+	return true
 }
 
-func (store *jsonNotificationConfigStore) loadNotificationScheduleState() (notificationScheduleState, error) {
-	content, err := os.ReadFile(store.notificationsFilePath)
+func (store *jsonNotificationConfigStore) loadState() (notificationStoreState, error) {
+	content, err := os.ReadFile(store.filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return notificationScheduleState{Notifications: make([]ScheduledNotification, 0)}, nil
+			return newNotificationStoreState(), nil
 		}
 
-		return notificationScheduleState{}, err
+		return notificationStoreState{}, err
 	}
 
-	var state notificationScheduleState
+	var state notificationStoreState
 	if err := json.Unmarshal(content, &state); err != nil {
-		return notificationScheduleState{}, err
+		return notificationStoreState{}, err
 	}
 
-	if state.Notifications == nil {
-		state.Notifications = make([]ScheduledNotification, 0)
+	if state.SchemaVersion != notificationStoreSchemaVersion {
+		return notificationStoreState{}, fmt.Errorf("unsupported notification store schema version %d", state.SchemaVersion)
 	}
 
-	return state, nil
-}
-
-func (store *jsonNotificationConfigStore) saveConfigState(state notificationConfigState) error {
 	if state.Guilds == nil {
 		state.Guilds = make(map[string]NotificationConfig)
 	}
 
-	if err := os.MkdirAll(filepath.Dir(store.configFilePath), 0o755); err != nil {
-		return err
+	if state.Notifications == nil {
+		state.Notifications = make([]ScheduledNotification, 0)
 	}
 
-	content, err := json.MarshalIndent(state, "", "  ")
+	return state, nil
+}
+
+func (store *jsonNotificationConfigStore) saveState(state notificationStoreState) error {
+	content, err := marshalNotificationStoreState(state)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(store.configFilePath, content, 0o644)
+	return datafile.WriteAtomic(store.filePath, content, 0o644)
 }
 
-func (store *jsonNotificationConfigStore) saveNotificationScheduleState(state notificationScheduleState) error {
-	if state.Notifications == nil {
-		state.Notifications = make([]ScheduledNotification, 0)
-	}
-
-	if err := os.MkdirAll(filepath.Dir(store.notificationsFilePath), 0o755); err != nil {
-		return err
+func newNotificationStoreState() notificationStoreState {
+	return notificationStoreState{
+		SchemaVersion: notificationStoreSchemaVersion,
+		Guilds:        make(map[string]NotificationConfig),
+		Notifications: make([]ScheduledNotification, 0),
 	}
+}
 
-	content, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return err
+// marshalNotificationStoreState encodes the state at the current schema
+// version.
+func marshalNotificationStoreState(state notificationStoreState) ([]byte, error) {
+	state.SchemaVersion = notificationStoreSchemaVersion
+	if state.Notifications == nil {
+		state.Notifications = make([]ScheduledNotification, 0)
 	}
 
-	return os.WriteFile(store.notificationsFilePath, content, 0o644)
+	return json.MarshalIndent(state, "", "  ")
 }
 
 func generateShortID() (string, error) {
@@ -489,93 +818,3 @@ func generateShortID() (string, error) {
 
 	return hex.EncodeToString(buffer), nil
 }
-
-func calculateInitialNextNotificationAt(baseHour string, everyMinutes int, now time.Time) (time.Time, error) {
-	if everyMinutes <= 0 {
-		return time.Time{}, errors.New("el valor every_minutes debe ser mayor a 0")
-	}
-
-	baseTime, err := time.Parse("15:04", baseHour)
-	if err != nil {
-		return time.Time{}, errors.New("el valor base_hour debe tener formato HH:MM (24h) en UTC")
-	}
-
-	normalizedNow := now.UTC()
-	next := time.Date(
-		normalizedNow.Year(),
-		normalizedNow.Month(),
-		normalizedNow.Day(),
-		baseTime.Hour(),
-		baseTime.Minute(),
-		0,
-		0,
-		time.UTC,
-	)
-
-	interval := time.Duration(everyMinutes) * time.Minute
-	for !next.After(normalizedNow) {
-		next = next.Add(interval)
-	}
-
-	return next, nil
-}
-
-func calculateInitialDailyNextNotificationAt(baseHour string, now time.Time) (time.Time, error) {
-	baseTime, err := time.Parse("15:04", baseHour)
-	if err != nil {
-		return time.Time{}, errors.New("el valor base_hour debe tener formato HH:MM (24h) en UTC")
-	}
-
-	normalizedNow := now.UTC()
-	next := time.Date(
-		normalizedNow.Year(),
-		normalizedNow.Month(),
-		normalizedNow.Day(),
-		baseTime.Hour(),
-		baseTime.Minute(),
-		0,
-		0,
-		time.UTC,
-	)
-
-	for !next.After(normalizedNow) {
-		next = next.Add(24 * time.Hour)
-	}
-
-	return next, nil
-}
-
-func calculateNextNotificationAt(notification ScheduledNotification, sentAt time.Time) (time.Time, error) {
-	nextNotificationAt := notification.NextNotificationAt
-
-	switch notification.Type {
-	case "daily":
-		for !nextNotificationAt.After(sentAt) {
-			nextNotificationAt = nextNotificationAt.Add(24 * time.Hour)
-		}
-		return nextNotificationAt, nil
-	case "byminutes":
-		if notification.EveryMinutes <= 0 {
-			return time.Time{}, errors.New("la notificación tiene un intervalo inválido")
-		}
-
-		interval := time.Duration(notification.EveryMinutes) * time.Minute
-		for !nextNotificationAt.After(sentAt) {
-			nextNotificationAt = nextNotificationAt.Add(interval)
-		}
-		return nextNotificationAt, nil
-	default:
-		return time.Time{}, errors.New("tipo de notificación no soportado")
-	}
-}
-
-func calculateNextFromBaseHour(notification ScheduledNotification, now time.Time) (time.Time, error) {
-	switch notification.Type {
-	case "daily":
-		return calculateInitialDailyNextNotificationAt(notification.BaseHour, now)
-	case "byminutes":
-		return calculateInitialNextNotificationAt(notification.BaseHour, notification.EveryMinutes, now)
-	default:
-		return time.Time{}, errors.New("tipo de notificación no soportado")
-	}
-}
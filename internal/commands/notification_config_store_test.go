@@ -3,14 +3,19 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/clock"
 )
 
 func TestJSONNotificationConfigStore(t *testing.T) {
 	filePath := filepath.Join(t.TempDir(), "notification_config.json")
-	store := NewJSONNotificationConfigStore(filePath)
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
 
 	if err := store.SetChannel(context.Background(), "guild-1", "channel-1"); err != nil {
 		t.Fatalf("expected nil error, got %v", err)
@@ -49,54 +54,56 @@ func TestJSONNotificationConfigStore(t *testing.T) {
 
 	content, err := os.ReadFile(filePath)
 	if err != nil {
-		t.Fatalf("expected config file to exist, got %v", err)
+		t.Fatalf("expected store file to exist, got %v", err)
 	}
 
-	var state notificationConfigState
+	var state notificationStoreState
 	if err := json.Unmarshal(content, &state); err != nil {
 		t.Fatalf("expected valid json, got %v", err)
 	}
 
-	guildConfig := state.Guilds["guild-1"]
-	if guildConfig.ChannelID != "channel-1" {
-		t.Fatalf("expected channel-1, got %q", guildConfig.ChannelID)
+	if state.SchemaVersion != notificationStoreSchemaVersion {
+		t.Fatalf("expected schema version %d, got %d", notificationStoreSchemaVersion, state.SchemaVersion)
 	}
 
-	if guildConfig.RoleID != "role-1" {
-		t.Fatalf("expected role-1, got %q", guildConfig.RoleID)
+	if storedConfig := state.Guilds["guild-1"]; storedConfig.ChannelID != "channel-1" || storedConfig.RoleID != "role-1" {
+		t.Fatalf("expected only guild settings to be stored, got %+v", storedConfig)
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(filePath), "notifications.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected no separate notifications file, got %v", err)
 	}
 
-	notificationFilePath := filepath.Join(filepath.Dir(filePath), "notifications.json")
-	notificationContent, err := os.ReadFile(notificationFilePath)
+	guildConfig, err := store.GetGuildConfig(context.Background(), "guild-1")
 	if err != nil {
-		t.Fatalf("expected notifications file to exist, got %v", err)
+		t.Fatalf("expected nil error, got %v", err)
 	}
 
-	var notificationState notificationScheduleState
-	if err := json.Unmarshal(notificationContent, &notificationState); err != nil {
-		t.Fatalf("expected valid notifications json, got %v", err)
+	if guildConfig.ChannelID != "channel-1" {
+		t.Fatalf("expected channel-1, got %q", guildConfig.ChannelID)
 	}
 
-	if len(guildConfig.ByMinutesNotifications) != 1 {
-		t.Fatalf("expected 1 byminutes config entry, got %d", len(guildConfig.ByMinutesNotifications))
+	if guildConfig.RoleID != "role-1" {
+		t.Fatalf("expected role-1, got %q", guildConfig.RoleID)
 	}
 
-	if len(guildConfig.DailyNotifications) != 1 {
-		t.Fatalf("expected 1 daily config entry, got %d", len(guildConfig.DailyNotifications))
+	guildNotifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil || len(guildNotifications) != 2 {
+		t.Fatalf("expected 2 guild notifications, got %+v (%v)", guildNotifications, err)
 	}
 
-	notification := guildConfig.ByMinutesNotifications[0]
-	if notification.ID == "" || notification.EveryMinutes != 240 || notification.BaseHour != "16:00" || notification.Title != "Recordatorio" || notification.Message != "Enviar reporte" {
+	notification := guildNotifications[0]
+	if notification.ID != byMinutesID || notification.EveryMinutes != 240 || notification.BaseHour != "16:00" || notification.Title != "Recordatorio" || notification.Message != "Enviar reporte" {
 		t.Fatalf("unexpected byminutes notification: %+v", notification)
 	}
 
-	if len(notificationState.Notifications) != 2 {
-		t.Fatalf("expected 2 scheduled notifications, got %d", len(notificationState.Notifications))
+	if len(state.Notifications) != 2 {
+		t.Fatalf("expected 2 scheduled notifications, got %d", len(state.Notifications))
 	}
 
 	foundByMinutes := false
 	foundDaily := false
-	for _, scheduled := range notificationState.Notifications {
+	for _, scheduled := range state.Notifications {
 		if scheduled.ID == byMinutesID {
 			foundByMinutes = true
 			if scheduled.Type != "byminutes" || scheduled.NextNotificationAt.IsZero() {
@@ -123,10 +130,914 @@ func TestJSONNotificationConfigStoreHandlesInvalidJSON(t *testing.T) {
 		t.Fatalf("failed to prepare invalid json file: %v", err)
 	}
 
-	store := NewJSONNotificationConfigStore(filePath)
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
 
 	err := store.SetChannel(context.Background(), "guild-1", "channel-1")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestCalculateNextNotificationAtKeepsWallClockAcrossDST(t *testing.T) {
+	location, err := loadNotificationLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	// 2026-03-08 is the spring-forward day in New York.
+	sentAt := time.Date(2026, 3, 7, 9, 0, 0, 0, location)
+	next, err := calculateNextNotificationAt(ScheduledNotification{
+		Type:               "daily",
+		BaseHour:           "09:00",
+		Timezone:           "America/New_York",
+		NextNotificationAt: sentAt.UTC(),
+	}, sentAt.UTC())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	localNext := next.In(location)
+	if localNext.Day() != 8 || localNext.Hour() != 9 || localNext.Minute() != 0 {
+		t.Fatalf("expected 2026-03-08 09:00 local time, got %v", localNext)
+	}
+
+	if next.Sub(sentAt) != 23*time.Hour {
+		t.Fatalf("expected a 23h gap across the DST change, got %v", next.Sub(sentAt))
+	}
+}
+
+func TestJSONNotificationConfigStoreSetTimezone(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+
+	if err := store.SetTimezone(context.Background(), "guild-1", "Marte/Olympus"); err == nil {
+		t.Fatal("expected error for unknown timezone, got nil")
+	}
+
+	dailyID, err := store.AddDailyNotification(context.Background(), "guild-1", DailyNotificationInput{
+		BaseHour: "08:00",
+		Title:    "Diario",
+		Message:  "Buenos días",
+	})
+	if err != nil {
+		t.Fatalf("expected nil error creating daily notification, got %v", err)
+	}
+
+	if err := store.SetTimezone(context.Background(), "guild-1", "America/Caracas"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	config, err := store.GetGuildConfig(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if config.Timezone != "America/Caracas" {
+		t.Fatalf("expected America/Caracas, got %q", config.Timezone)
+	}
+
+	notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 1 || notifications[0].ID != dailyID {
+		t.Fatalf("unexpected notifications: %+v", notifications)
+	}
+
+	location, _ := time.LoadLocation("America/Caracas")
+	localNext := notifications[0].NextNotificationAt.In(location)
+	if notifications[0].Timezone != "America/Caracas" || localNext.Hour() != 8 || localNext.Minute() != 0 {
+		t.Fatalf("expected next notification at 08:00 Caracas time, got %v (%q)", localNext, notifications[0].Timezone)
+	}
+}
+
+func TestJSONNotificationConfigStorePersistsStyleAndDeliverySettings(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+	style := NotificationStyle{Color: 0x5865F2, ImageURL: "https://example.com/image.png"}
+
+	if _, err := store.AddDailyNotification(context.Background(), "guild-1", DailyNotificationInput{
+		BaseHour:          "08:00",
+		Title:             "Diario",
+		Message:           "Buenos días",
+		NotificationStyle: style,
+	}); err != nil {
+		t.Fatalf("expected nil error creating daily notification, got %v", err)
+	}
+
+	if err := store.SetPlainText(context.Background(), "guild-1", true); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := store.SetMentions(context.Background(), "guild-1", "<@&1>,@here"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := store.SetMentions(context.Background(), "guild-1", "@todos"); err == nil {
+		t.Fatal("expected error for invalid mentions, got nil")
+	}
+
+	if err := store.SetManagerRole(context.Background(), "guild-1", "managers"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	reopened := NewJSONNotificationConfigStore(filePath, clock.System())
+	config, err := reopened.GetGuildConfig(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if !config.PlainText || config.Mentions != "<@&1> @here" || config.ManagerRoleID != "managers" {
+		t.Fatalf("unexpected guild config: %+v", config)
+	}
+
+	notifications, err := reopened.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil || len(notifications) != 1 || notifications[0].NotificationStyle != style {
+		t.Fatalf("expected styled notification, got %+v (%v)", notifications, err)
+	}
+}
+
+func TestJSONNotificationConfigStoreCronNotification(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+
+	cronID, err := store.AddCronNotification(context.Background(), "guild-1", CronNotificationInput{
+		CronExpression: "0 9 * * MON-FRI",
+		Title:          "Standup",
+		Message:        "Reunión diaria",
+	})
+	if err != nil {
+		t.Fatalf("expected nil error creating cron notification, got %v", err)
+	}
+
+	notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 1 || notifications[0].Type != "cron" || notifications[0].CronExpression != "0 9 * * MON-FRI" {
+		t.Fatalf("unexpected notifications: %+v", notifications)
+	}
+
+	next := notifications[0].NextNotificationAt
+	if next.Weekday() == time.Saturday || next.Weekday() == time.Sunday || next.Hour() != 9 || next.Minute() != 0 {
+		t.Fatalf("expected next notification on a weekday at 09:00 UTC, got %v", next)
+	}
+
+	if err := store.DeleteNotification(context.Background(), "guild-1", cronID); err != nil {
+		t.Fatalf("expected nil error deleting cron notification, got %v", err)
+	}
+
+	notifications, err = store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 0 {
+		t.Fatalf("expected cron notification to be removed, got %+v", notifications)
+	}
+}
+
+func TestJSONNotificationConfigStoreRetiresExhaustedRecurrence(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+
+	id, err := store.AddRecurrenceNotification(context.Background(), "guild-1", RecurrenceNotificationInput{
+		RecurrenceRule: "FREQ=DAILY;COUNT=1",
+		BaseHour:       "09:00",
+		Title:          "Una vez",
+		Message:        "Solo una vez",
+	})
+	if err != nil {
+		t.Fatalf("expected nil error creating rrule notification, got %v", err)
+	}
+
+	notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("expected one notification, got %d (%v)", len(notifications), err)
+	}
+
+	if err := store.MarkNotificationSent(context.Background(), id, notifications[0].NextNotificationAt); err != nil {
+		t.Fatalf("expected nil error marking notification, got %v", err)
+	}
+
+	notifications, err = store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 0 {
+		t.Fatalf("expected exhausted notification to be retired, got %+v", notifications)
+	}
+
+	notifications, err = store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 0 {
+		t.Fatalf("expected rrule notification to be removed, got %+v", notifications)
+	}
+}
+
+func TestJSONNotificationConfigStoreOnceNotificationFiresOnce(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+
+	if _, err := store.AddOnceNotification(context.Background(), "guild-1", OnceNotificationInput{
+		Date:     "2020-01-01",
+		BaseHour: "21:00",
+		Title:    "Pasado",
+		Message:  "Ya pasó",
+	}); err == nil {
+		t.Fatal("expected error for a reminder in the past, got nil")
+	}
+
+	date := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	id, err := store.AddOnceNotification(context.Background(), "guild-1", OnceNotificationInput{
+		Date:     date,
+		BaseHour: "21:00",
+		Title:    "Raid",
+		Message:  "Raid esta noche",
+	})
+	if err != nil {
+		t.Fatalf("expected nil error creating once notification, got %v", err)
+	}
+
+	expectedAt, _ := time.Parse("2006-01-02 15:04", date+" 21:00")
+	due, err := store.ListDueNotifications(context.Background(), expectedAt)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(due) != 1 || due[0].ID != id || due[0].Type != "once" {
+		t.Fatalf("expected reminder to be due at %v, got %+v", expectedAt, due)
+	}
+
+	if err := store.MarkNotificationSent(context.Background(), id, expectedAt); err != nil {
+		t.Fatalf("expected nil error marking reminder, got %v", err)
+	}
+
+	notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 0 {
+		t.Fatalf("expected reminder to be removed after firing, got %+v", notifications)
+	}
+
+	notifications, err = store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 0 {
+		t.Fatalf("expected reminder notification to be removed, got %+v", notifications)
+	}
+}
+
+func TestJSONNotificationConfigStoreRetiresAfterMaxOccurrences(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+
+	id, err := store.AddByMinutesNotification(context.Background(), "guild-1", ByMinutesNotificationInput{
+		EveryMinutes:       30,
+		BaseHour:           "00:00",
+		Title:              "Agua",
+		Message:            "Toma agua",
+		NotificationLimits: NotificationLimits{MaxOccurrences: 2},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	for sent := 1; sent <= 2; sent++ {
+		notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+		if err != nil || len(notifications) != 1 {
+			t.Fatalf("expected notification before delivery %d, got %+v (%v)", sent, notifications, err)
+		}
+
+		if err := store.MarkNotificationSent(context.Background(), id, notifications[0].NextNotificationAt); err != nil {
+			t.Fatalf("expected nil error marking delivery %d, got %v", sent, err)
+		}
+	}
+
+	notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 0 {
+		t.Fatalf("expected notification to be retired after max occurrences, got %+v", notifications)
+	}
+
+	notifications, err = store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 0 {
+		t.Fatalf("expected byminutes notification to be removed, got %+v", notifications)
+	}
+}
+
+func TestJSONNotificationConfigStoreHonorsStartAndEndDates(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+
+	startsAt := time.Date(time.Now().UTC().Year()+1, 3, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.AddDate(0, 0, 1).Add(12 * time.Hour)
+	id, err := store.AddDailyNotification(context.Background(), "guild-1", DailyNotificationInput{
+		BaseHour:           "09:00",
+		Title:              "Temporada",
+		Message:            "Evento de temporada",
+		NotificationLimits: NotificationLimits{StartsAt: startsAt, EndsAt: endsAt},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("expected one notification, got %+v (%v)", notifications, err)
+	}
+
+	if expected := startsAt.Add(9 * time.Hour); !notifications[0].NextNotificationAt.Equal(expected) {
+		t.Fatalf("expected first notification at %v, got %v", expected, notifications[0].NextNotificationAt)
+	}
+
+	if err := store.MarkNotificationSent(context.Background(), id, notifications[0].NextNotificationAt); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	notifications, err = store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("expected notification to remain before end date, got %+v (%v)", notifications, err)
+	}
+
+	if err := store.MarkNotificationSent(context.Background(), id, notifications[0].NextNotificationAt); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	notifications, err = store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(notifications) != 0 {
+		t.Fatalf("expected notification to be retired after end date, got %+v", notifications)
+	}
+
+	if _, err := store.AddDailyNotification(context.Background(), "guild-1", DailyNotificationInput{
+		BaseHour:           "09:00",
+		Title:              "Invertida",
+		Message:            "Fechas invertidas",
+		NotificationLimits: NotificationLimits{StartsAt: endsAt, EndsAt: startsAt},
+	}); err == nil {
+		t.Fatal("expected error when end date precedes start date, got nil")
+	}
+}
+
+func TestJSONNotificationConfigStoreCatchUpAfterDowntime(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+
+	addDaily := func(title, policy string) string {
+		id, err := store.AddDailyNotification(context.Background(), "guild-1", DailyNotificationInput{
+			BaseHour:           "09:00",
+			Title:              title,
+			Message:            title,
+			NotificationLimits: NotificationLimits{CatchUpPolicy: policy},
+		})
+		if err != nil {
+			t.Fatalf("expected nil error creating %s, got %v", title, err)
+		}
+
+		return id
+	}
+
+	skipID := addDaily("Sin recuperar", "skip")
+	allID := addDaily("Todas", "fire_all_up_to_2")
+	onceID := addDaily("Una", "")
+	if err := store.SetCatchUpPolicy(context.Background(), "guild-1", "fire_once"); err != nil {
+		t.Fatalf("expected nil error setting guild policy, got %v", err)
+	}
+
+	notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil || len(notifications) != 3 {
+		t.Fatalf("expected three notifications, got %+v (%v)", notifications, err)
+	}
+
+	first := notifications[0].NextNotificationAt
+	now := first.AddDate(0, 0, 3).Add(time.Hour)
+	if err := store.RecalculateAllNextNotifications(context.Background(), now); err != nil {
+		t.Fatalf("expected nil error recalculating, got %v", err)
+	}
+
+	byID := func() map[string]ScheduledNotification {
+		notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		result := make(map[string]ScheduledNotification, len(notifications))
+		for _, notification := range notifications {
+			result[notification.ID] = notification
+		}
+
+		return result
+	}
+
+	scheduled := byID()
+	if next := scheduled[skipID].NextNotificationAt; !next.Equal(first.AddDate(0, 0, 4)) || scheduled[skipID].IsCatchingUp() {
+		t.Fatalf("expected skip notification to jump to the next future occurrence, got %+v", scheduled[skipID])
+	}
+
+	if next := scheduled[onceID].NextNotificationAt; !next.Equal(first.AddDate(0, 0, 3)) || !scheduled[onceID].IsCatchingUp() {
+		t.Fatalf("expected guild fire_once to deliver only the latest missed occurrence, got %+v", scheduled[onceID])
+	}
+
+	if next := scheduled[allID].NextNotificationAt; !next.Equal(first.AddDate(0, 0, 2)) || !scheduled[allID].CatchUpUntil.Equal(first.AddDate(0, 0, 3)) {
+		t.Fatalf("expected fire_all_up_to_2 to start at the second to last missed occurrence, got %+v", scheduled[allID])
+	}
+
+	due, err := store.ListDueNotifications(context.Background(), now)
+	if err != nil || len(due) != 2 {
+		t.Fatalf("expected two catch-up notifications due, got %+v (%v)", due, err)
+	}
+
+	if err := store.MarkNotificationSent(context.Background(), allID, now); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if notification := byID()[allID]; !notification.NextNotificationAt.Equal(first.AddDate(0, 0, 3)) || !notification.IsCatchingUp() {
+		t.Fatalf("expected next missed occurrence after first catch-up delivery, got %+v", notification)
+	}
+
+	if err := store.MarkNotificationSent(context.Background(), allID, now); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	notification := byID()[allID]
+	if !notification.NextNotificationAt.Equal(first.AddDate(0, 0, 4)) || notification.IsCatchingUp() {
+		t.Fatalf("expected schedule to resume after catching up, got %+v", notification)
+	}
+
+	if !notification.LastSentAt.Equal(now) || notification.OccurrencesSent != 2 {
+		t.Fatalf("expected last sent timestamp and count to be recorded, got %+v", notification)
+	}
+}
+
+func TestParseCatchUpPolicy(t *testing.T) {
+	tests := []struct {
+		value  string
+		policy string
+		keep   int
+		valid  bool
+	}{
+		{value: "skip", policy: "skip", keep: 0, valid: true},
+		{value: " Fire_Once ", policy: "fire_once", keep: 1, valid: true},
+		{value: "fire_all_up_to_5", policy: "fire_all_up_to_5", keep: 5, valid: true},
+		{value: "fire_all_up_to_100", policy: "fire_all_up_to_100", keep: 100, valid: true},
+		{value: "fire_all_up_to_0"},
+		{value: "fire_all_up_to_101"},
+		{value: "fire_all_up_to_999999999999"},
+		{value: "fire_all_up_to_x"},
+		{value: "always"},
+	}
+
+	for _, test := range tests {
+		policy, keep, err := parseCatchUpPolicy(test.value)
+		if !test.valid {
+			if err == nil {
+				t.Fatalf("expected error for %q, got nil", test.value)
+			}
+			continue
+		}
+
+		if err != nil || policy != test.policy || keep != test.keep {
+			t.Fatalf("parseCatchUpPolicy(%q) = %q, %d, %v", test.value, policy, keep, err)
+		}
+	}
+
+	if _, _, err := parseCatchUpPolicy("fire_all_up_to_101"); err == nil || err.Error() != "opción inválida: policy debe ser fire_all_up_to_N con N de 1 a 100" {
+		t.Fatalf("expected over-limit error, got %v", err)
+	}
+
+	if _, _, err := parseCatchUpPolicy("always"); err == nil || err.Error() != "opción inválida: policy debe ser skip, fire_once o fire_all_up_to_N" {
+		t.Fatalf("expected unknown policy error, got %v", err)
+	}
+}
+
+func TestCollectMissedOccurrencesKeepsTheMostRecent(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 30, 0, time.UTC)
+	notification := ScheduledNotification{
+		Type:               "byminutes",
+		EveryMinutes:       1,
+		NextNotificationAt: now.AddDate(-1, 0, 0),
+		NotificationLimits: NotificationLimits{MaxOccurrences: 10},
+		OccurrencesSent:    8,
+	}
+
+	missed, err := collectMissedOccurrences(notification, now, 3)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := []time.Time{now.Add(-time.Minute), now}
+	if !slices.EqualFunc(missed, expected, time.Time.Equal) {
+		t.Fatalf("expected the occurrences right before now, got %v", missed)
+	}
+
+	notification.EndsAt = now.Add(-24*time.Hour - 10*time.Second)
+	missed, err = collectMissedOccurrences(notification, now, 1)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(missed) != 1 || !missed[0].Equal(now.Add(-24*time.Hour-time.Minute)) {
+		t.Fatalf("expected the last occurrence before the end date, got %v", missed)
+	}
+}
+
+func TestCalculateInitialWeeklyNextNotificationAt(t *testing.T) {
+	location, err := loadNotificationLocation("America/Caracas")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	weekdays := []time.Weekday{time.Monday, time.Wednesday}
+	// 2026-10-19 is a Monday.
+	testCases := []struct {
+		now      time.Time
+		expected time.Time
+	}{
+		{time.Date(2026, 10, 19, 8, 0, 0, 0, location), time.Date(2026, 10, 19, 9, 0, 0, 0, location)},
+		{time.Date(2026, 10, 19, 9, 0, 0, 0, location), time.Date(2026, 10, 21, 9, 0, 0, 0, location)},
+		{time.Date(2026, 10, 22, 9, 0, 0, 0, location), time.Date(2026, 10, 26, 9, 0, 0, 0, location)},
+	}
+
+	for _, testCase := range testCases {
+		next, err := calculateInitialWeeklyNextNotificationAt("09:00", weekdays, location, testCase.now)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if !next.Equal(testCase.expected) {
+			t.Fatalf("from %v: expected %v, got %v", testCase.now, testCase.expected, next)
+		}
+	}
+
+	if _, err := calculateInitialWeeklyNextNotificationAt("09:00", nil, location, time.Now()); err == nil {
+		t.Fatal("expected error without weekdays, got nil")
+	}
+}
+
+func TestCalculateInitialMonthlyNextNotificationAt(t *testing.T) {
+	location, err := loadNotificationLocation("America/Caracas")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		notification ScheduledNotification
+		now          time.Time
+		expected     time.Time
+	}{
+		{
+			name:         "day 31 falls on the last day of a short month",
+			notification: ScheduledNotification{BaseHour: "10:00", MonthDay: 31},
+			now:          time.Date(2027, 1, 31, 11, 0, 0, 0, location),
+			expected:     time.Date(2027, 2, 28, 10, 0, 0, 0, location),
+		},
+		{
+			name:         "day 15 later this month",
+			notification: ScheduledNotification{BaseHour: "10:00", MonthDay: 15},
+			now:          time.Date(2026, 10, 14, 11, 0, 0, 0, location),
+			expected:     time.Date(2026, 10, 15, 10, 0, 0, 0, location),
+		},
+		{
+			name:         "second saturday",
+			notification: ScheduledNotification{BaseHour: "10:00", MonthWeek: 2, Weekdays: []time.Weekday{time.Saturday}},
+			now:          time.Date(2026, 10, 17, 11, 0, 0, 0, location),
+			expected:     time.Date(2026, 11, 14, 10, 0, 0, 0, location),
+		},
+		{
+			name:         "last friday",
+			notification: ScheduledNotification{BaseHour: "10:00", MonthWeek: -1, Weekdays: []time.Weekday{time.Friday}},
+			now:          time.Date(2026, 10, 17, 11, 0, 0, 0, location),
+			expected:     time.Date(2026, 10, 30, 10, 0, 0, 0, location),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			next, err := calculateInitialMonthlyNextNotificationAt(testCase.notification, location, testCase.now)
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			if !next.Equal(testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, next.In(location))
+			}
+		})
+	}
+}
+
+func TestJSONNotificationConfigStoreUpdateNotification(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := NewJSONNotificationConfigStore(filePath, fakeClock)
+	ctx := context.Background()
+
+	id, err := store.AddDailyNotification(ctx, "guild-1", DailyNotificationInput{BaseHour: "09:00", Title: "Diario", Message: "Hola"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	notifications, err := store.ListGuildNotifications(ctx, "guild-1")
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("expected one notification, got %+v (%v)", notifications, err)
+	}
+
+	if err := store.MarkNotificationSent(ctx, id, notifications[0].NextNotificationAt); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	baseHour, message := "18:30", "Adiós"
+	updated, err := store.UpdateNotification(ctx, "guild-1", id, NotificationUpdate{BaseHour: &baseHour, Message: &message})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if updated.ID != id || updated.Title != "Diario" || updated.Message != "Adiós" || updated.OccurrencesSent != 1 {
+		t.Fatalf("unexpected updated notification: %+v", updated)
+	}
+
+	if expected := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC); !updated.NextNotificationAt.Equal(expected) {
+		t.Fatalf("expected next notification at %v, got %v", expected, updated.NextNotificationAt)
+	}
+
+	notifications, err = store.ListGuildNotifications(ctx, "guild-1")
+	if err != nil || len(notifications) != 1 || notifications[0].BaseHour != "18:30" {
+		t.Fatalf("expected guild notifications to reflect the edit, got %+v (%v)", notifications, err)
+	}
+
+	// Past 18:30, a reschedule would move the notification to tomorrow.
+	fakeClock.Advance(7 * time.Hour)
+	title := "Diario nuevo"
+	retitled, err := store.UpdateNotification(ctx, "guild-1", id, NotificationUpdate{Title: &title})
+	if err != nil || retitled.Title != title || !retitled.NextNotificationAt.Equal(updated.NextNotificationAt) {
+		t.Fatalf("expected a title edit to keep the next notification, got %+v (%v)", retitled, err)
+	}
+
+	everyMinutes := 30
+	if _, err := store.UpdateNotification(ctx, "guild-1", id, NotificationUpdate{EveryMinutes: &everyMinutes}); err == nil {
+		t.Fatal("expected error editing every_minutes of a daily notification")
+	}
+
+	maxOccurrences := 1
+	if _, err := store.UpdateNotification(ctx, "guild-1", id, NotificationUpdate{MaxOccurrences: &maxOccurrences}); err == nil {
+		t.Fatal("expected error when the edit leaves no future occurrences")
+	}
+
+	if _, err := store.UpdateNotification(ctx, "guild-2", id, NotificationUpdate{Message: &message}); err == nil {
+		t.Fatal("expected error editing a notification from another guild")
+	}
+
+	notifications, err = store.ListGuildNotifications(ctx, "guild-1")
+	if err != nil || len(notifications) != 1 || notifications[0].MaxOccurrences != 0 || notifications[0].BaseHour != "18:30" {
+		t.Fatalf("expected rejected edits to leave the notification unchanged, got %+v (%v)", notifications, err)
+	}
+}
+
+func TestUpdateScheduledNotificationKeepsScheduleOnContentEdits(t *testing.T) {
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	notification := ScheduledNotification{
+		ID:                 "abc123",
+		Type:               "daily",
+		BaseHour:           "09:00",
+		Title:              "Diario",
+		Message:            "Hola",
+		NextNotificationAt: time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC),
+		CatchUpUntil:       time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+	}
+
+	title, color := "Nuevo título", 0xFF0000
+	updated, err := updateScheduledNotification(notification, NotificationUpdate{Title: &title, Color: &color}, now)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if updated.Title != title || updated.Color != color {
+		t.Fatalf("expected the edit to be applied, got %+v", updated)
+	}
+
+	if !updated.NextNotificationAt.Equal(notification.NextNotificationAt) || !updated.CatchUpUntil.Equal(notification.CatchUpUntil) {
+		t.Fatalf("expected the pending occurrence and catch-up to be kept, got next=%v catch_up_until=%v", updated.NextNotificationAt, updated.CatchUpUntil)
+	}
+
+	baseHour := "18:00"
+	updated, err = updateScheduledNotification(notification, NotificationUpdate{BaseHour: &baseHour}, now)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if !updated.NextNotificationAt.Equal(time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)) || updated.IsCatchingUp() {
+		t.Fatalf("expected a schedule edit to reschedule, got next=%v catch_up_until=%v", updated.NextNotificationAt, updated.CatchUpUntil)
+	}
+}
+
+func TestUpdateScheduledNotificationChangesMonthlySchedule(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	notification := ScheduledNotification{Type: "monthly", BaseHour: "10:00", MonthDay: 20, NextNotificationAt: time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)}
+
+	monthDay, monthWeek, weekday := 0, -1, time.Friday
+	updated, err := updateScheduledNotification(notification, NotificationUpdate{MonthDay: &monthDay, MonthWeek: &monthWeek, Weekday: &weekday}, now)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if updated.MonthDay != 0 || updated.MonthWeek != -1 || !slices.Equal(updated.Weekdays, []time.Weekday{time.Friday}) {
+		t.Fatalf("expected the last friday of the month, got %+v", updated)
+	}
+
+	if expected := time.Date(2026, 10, 30, 10, 0, 0, 0, time.UTC); !updated.NextNotificationAt.Equal(expected) {
+		t.Fatalf("expected next notification at %v, got %v", expected, updated.NextNotificationAt)
+	}
+
+	monthDay = 5
+	updated, err = updateScheduledNotification(updated, NotificationUpdate{MonthDay: &monthDay}, now)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if updated.MonthDay != 5 || updated.MonthWeek != 0 || updated.Weekdays != nil {
+		t.Fatalf("expected day 5 of the month, got %+v", updated)
+	}
+
+	daily := ScheduledNotification{Type: "daily", BaseHour: "10:00"}
+	if _, err := updateScheduledNotification(daily, NotificationUpdate{MonthDay: &monthDay}, now); err == nil {
+		t.Fatal("expected error editing the day of a daily notification")
+	}
+}
+
+func TestJSONNotificationConfigStorePauseAllKeepsFinishedNotifications(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := NewJSONNotificationConfigStore(filepath.Join(t.TempDir(), "notification_config.json"), fakeClock)
+	testPauseAllKeepsFinishedNotifications(t, store, fakeClock)
+}
+
+// testPauseAllKeepsFinishedNotifications checks that pausing a guild until
+// after a reminder leaves the reminder as it was instead of retiring it.
+func testPauseAllKeepsFinishedNotifications(t *testing.T, store NotificationConfigStore, clk clock.Clock) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := store.AddDailyNotification(ctx, "guild-1", DailyNotificationInput{BaseHour: "13:00", Title: "Diario", Message: "Hola"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	reminderID, err := store.AddOnceNotification(ctx, "guild-1", OnceNotificationInput{Date: "2024-03-03", BaseHour: "09:00", Title: "Recordatorio", Message: "Pronto"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	paused, skipped, err := store.PauseGuildNotifications(ctx, "guild-1", clk.Now().AddDate(0, 0, 5))
+	if err != nil || paused != 1 || skipped != 1 {
+		t.Fatalf("expected one notification paused and one skipped, got %d and %d (%v)", paused, skipped, err)
+	}
+
+	notifications, err := store.ListGuildNotifications(ctx, "guild-1")
+	if err != nil || len(notifications) != 2 {
+		t.Fatalf("expected both notifications to be kept, got %+v (%v)", notifications, err)
+	}
+
+	for _, notification := range notifications {
+		if notification.ID != reminderID {
+			continue
+		}
+
+		if notification.Paused || !notification.NextNotificationAt.Equal(time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC)) {
+			t.Fatalf("expected the reminder to be left as it was, got %+v", notification)
+		}
+	}
+}
+
+func TestJSONNotificationConfigStoreResumeRetiresExpiredReminder(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := NewJSONNotificationConfigStore(filepath.Join(t.TempDir(), "notification_config.json"), fakeClock)
+	testResumeRetiresExpiredReminder(t, store, fakeClock)
+}
+
+// testResumeRetiresExpiredReminder checks that resuming a reminder whose
+// date passed during the pause retires it instead of leaving it paused.
+func testResumeRetiresExpiredReminder(t *testing.T, store NotificationConfigStore, clk *clock.Fake) {
+	t.Helper()
+	ctx := context.Background()
+
+	reminderID, err := store.AddOnceNotification(ctx, "guild-1", OnceNotificationInput{Date: "2024-03-03", BaseHour: "09:00", Title: "Recordatorio", Message: "Pronto"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if _, err := store.PauseNotification(ctx, "guild-1", reminderID, time.Time{}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	clk.Advance(5 * 24 * time.Hour)
+	if _, err := store.ResumeNotification(ctx, "guild-1", reminderID); !errors.Is(err, ErrNotificationExpired) {
+		t.Fatalf("expected ErrNotificationExpired, got %v", err)
+	}
+
+	notifications, err := store.ListGuildNotifications(ctx, "guild-1")
+	if err != nil || len(notifications) != 0 {
+		t.Fatalf("expected the reminder to be retired, got %+v (%v)", notifications, err)
+	}
+}
+
+func TestJSONNotificationConfigStorePauseAndResume(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notification_config.json")
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := NewJSONNotificationConfigStore(filePath, fakeClock)
+	ctx := context.Background()
+
+	dailyID, err := store.AddDailyNotification(ctx, "guild-1", DailyNotificationInput{BaseHour: "13:00", Title: "Diario", Message: "Hola"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	weeklyID, err := store.AddWeeklyNotification(ctx, "guild-1", WeeklyNotificationInput{BaseHour: "13:00", Weekdays: []time.Weekday{time.Friday}, Title: "Semanal", Message: "Hola"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if _, err := store.PauseNotification(ctx, "guild-1", dailyID, time.Time{}); err != nil {
+		t.Fatalf("expected nil error pausing, got %v", err)
+	}
+
+	// Friday 2024-03-08 13:00 is the first weekly occurrence after the pause.
+	resumeAt := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	paused, err := store.PauseNotification(ctx, "guild-1", weeklyID, resumeAt)
+	if err != nil {
+		t.Fatalf("expected nil error pausing until a date, got %v", err)
+	}
+
+	if expected := time.Date(2024, 3, 8, 13, 0, 0, 0, time.UTC); !paused.NextNotificationAt.Equal(expected) {
+		t.Fatalf("expected paused notification to skip to %v, got %v", expected, paused.NextNotificationAt)
+	}
+
+	fakeClock.Advance(2 * time.Hour)
+	due, err := store.ListDueNotifications(ctx, fakeClock.Now())
+	if err != nil || len(due) != 0 {
+		t.Fatalf("expected paused notifications not to be due, got %+v (%v)", due, err)
+	}
+
+	fakeClock.Set(time.Date(2024, 3, 8, 13, 0, 0, 0, time.UTC))
+	due, err = store.ListDueNotifications(ctx, fakeClock.Now())
+	if err != nil || len(due) != 1 || due[0].ID != weeklyID {
+		t.Fatalf("expected weekly notification to resume on its own, got %+v (%v)", due, err)
+	}
+
+	if err := store.MarkNotificationSent(ctx, weeklyID, fakeClock.Now()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if _, err := store.ResumeNotification(ctx, "guild-1", weeklyID); err == nil {
+		t.Fatal("expected error resuming a notification that is not paused")
+	}
+
+	resumed, err := store.ResumeNotification(ctx, "guild-1", dailyID)
+	if err != nil {
+		t.Fatalf("expected nil error resuming, got %v", err)
+	}
+
+	// The occurrences missed while paused are skipped, not caught up.
+	if resumed.IsPaused(fakeClock.Now()) || !resumed.NextNotificationAt.Equal(time.Date(2024, 3, 9, 13, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected resumed notification: %+v", resumed)
+	}
+
+	count, skipped, err := store.PauseGuildNotifications(ctx, "guild-1", time.Time{})
+	if err != nil || count != 2 || skipped != 0 {
+		t.Fatalf("expected two notifications paused, got %d and %d skipped (%v)", count, skipped, err)
+	}
+
+	count, err = store.ResumeGuildNotifications(ctx, "guild-1")
+	if err != nil || count != 2 {
+		t.Fatalf("expected two notifications resumed, got %d (%v)", count, err)
+	}
+
+	notifications, err := store.ListGuildNotifications(ctx, "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	for _, notification := range notifications {
+		if notification.Paused {
+			t.Fatalf("expected every notification to be resumed, got %+v", notification)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package commands
+
+import "time"
+
+func (input ByMinutesNotificationInput) scheduledNotification() ScheduledNotification {
+	return ScheduledNotification{
+		Type:               "byminutes",
+		EveryMinutes:       input.EveryMinutes,
+		BaseHour:           input.BaseHour,
+		Title:              input.Title,
+		Message:            input.Message,
+		NotificationLimits: input.NotificationLimits,
+		NotificationStyle:  input.NotificationStyle,
+		NotificationTarget: input.NotificationTarget,
+	}
+}
+
+func (input DailyNotificationInput) scheduledNotification() ScheduledNotification {
+	return ScheduledNotification{
+		Type:               "daily",
+		BaseHour:           input.BaseHour,
+		Title:              input.Title,
+		Message:            input.Message,
+		NotificationLimits: input.NotificationLimits,
+		NotificationStyle:  input.NotificationStyle,
+		NotificationTarget: input.NotificationTarget,
+	}
+}
+
+func (input WeeklyNotificationInput) scheduledNotification() ScheduledNotification {
+	return ScheduledNotification{
+		Type:               "weekly",
+		BaseHour:           input.BaseHour,
+		Weekdays:           input.Weekdays,
+		Title:              input.Title,
+		Message:            input.Message,
+		NotificationLimits: input.NotificationLimits,
+		NotificationStyle:  input.NotificationStyle,
+		NotificationTarget: input.NotificationTarget,
+	}
+}
+
+func (input MonthlyNotificationInput) scheduledNotification() ScheduledNotification {
+	notification := ScheduledNotification{
+		Type:               "monthly",
+		BaseHour:           input.BaseHour,
+		MonthDay:           input.MonthDay,
+		Title:              input.Title,
+		Message:            input.Message,
+		NotificationLimits: input.NotificationLimits,
+		NotificationStyle:  input.NotificationStyle,
+		NotificationTarget: input.NotificationTarget,
+	}
+
+	if input.MonthDay == 0 {
+		notification.MonthWeek = input.MonthWeek
+		notification.Weekdays = []time.Weekday{input.Weekday}
+	}
+
+	return notification
+}
+
+func (input CronNotificationInput) scheduledNotification() ScheduledNotification {
+	return ScheduledNotification{
+		Type:               "cron",
+		CronExpression:     input.CronExpression,
+		Title:              input.Title,
+		Message:            input.Message,
+		NotificationLimits: input.NotificationLimits,
+		NotificationStyle:  input.NotificationStyle,
+		NotificationTarget: input.NotificationTarget,
+	}
+}
+
+func (input RecurrenceNotificationInput) scheduledNotification() ScheduledNotification {
+	return ScheduledNotification{
+		Type:               "rrule",
+		RecurrenceRule:     input.RecurrenceRule,
+		BaseHour:           input.BaseHour,
+		Title:              input.Title,
+		Message:            input.Message,
+		NotificationLimits: input.NotificationLimits,
+		NotificationStyle:  input.NotificationStyle,
+		NotificationTarget: input.NotificationTarget,
+	}
+}
+
+func (input OnceNotificationInput) scheduledNotification() ScheduledNotification {
+	return ScheduledNotification{
+		Type:               "once",
+		Date:               input.Date,
+		BaseHour:           input.BaseHour,
+		Title:              input.Title,
+		Message:            input.Message,
+		NotificationLimits: input.NotificationLimits,
+		NotificationStyle:  input.NotificationStyle,
+		NotificationTarget: input.NotificationTarget,
+	}
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+// notificationLimitOptions are the optional options shared by every command
+// that creates a notification.
+func notificationLimitOptions() []discord.SlashCommandOption {
+	return []discord.SlashCommandOption{
+		{
+			Name:        "starts_at",
+			Description: "Fecha de inicio, formato AAAA-MM-DD o AAAA-MM-DD HH:MM",
+			Type:        discord.SlashCommandOptionTypeString,
+		},
+		{
+			Name:        "ends_at",
+			Description: "Fecha de fin (incluida), formato AAAA-MM-DD o AAAA-MM-DD HH:MM",
+			Type:        discord.SlashCommandOptionTypeString,
+		},
+		{
+			Name:        "max_occurrences",
+			Description: "Cantidad máxima de envíos",
+			Type:        discord.SlashCommandOptionTypeInteger,
+		},
+		{
+			Name:        "catch_up",
+			Description: "Envíos perdidos mientras el bot estaba apagado: skip, fire_once o fire_all_up_to_N",
+			Type:        discord.SlashCommandOptionTypeString,
+		},
+	}
+}
+
+// parseNotificationLimits reads the limit options, interpreting dates in the
+// guild timezone. A date without time starts at 00:00 for starts_at and
+// covers the whole day for ends_at.
+func parseNotificationLimits(options map[string]string, timezone string) (NotificationLimits, error) {
+	var limits NotificationLimits
+
+	location, err := loadNotificationLocation(timezone)
+	if err != nil {
+		return NotificationLimits{}, err
+	}
+
+	if raw := strings.TrimSpace(options["starts_at"]); raw != "" {
+		startsAt, _, err := parseLimitDateTime(raw, location)
+		if err != nil {
+			return NotificationLimits{}, InvalidOptionError("starts_at", "una fecha AAAA-MM-DD o AAAA-MM-DD HH:MM")
+		}
+
+		limits.StartsAt = startsAt.UTC()
+	}
+
+	if raw := strings.TrimSpace(options["ends_at"]); raw != "" {
+		endsAt, hasTime, err := parseLimitDateTime(raw, location)
+		if err != nil {
+			return NotificationLimits{}, InvalidOptionError("ends_at", "una fecha AAAA-MM-DD o AAAA-MM-DD HH:MM")
+		}
+
+		if !hasTime {
+			endsAt = time.Date(endsAt.Year(), endsAt.Month(), endsAt.Day()+1, 0, 0, 0, 0, location).Add(-time.Nanosecond)
+		}
+
+		limits.EndsAt = endsAt.UTC()
+	}
+
+	if raw := strings.TrimSpace(options["max_occurrences"]); raw != "" {
+		maxOccurrences, err := strconv.Atoi(raw)
+		if err != nil || maxOccurrences <= 0 {
+			return NotificationLimits{}, InvalidOptionError("max_occurrences", "un número entero mayor a 0")
+		}
+
+		limits.MaxOccurrences = maxOccurrences
+	}
+
+	if raw := strings.TrimSpace(options["catch_up"]); raw != "" {
+		policy, _, err := parseCatchUpPolicy(raw)
+		if err != nil {
+			return NotificationLimits{}, err
+		}
+
+		limits.CatchUpPolicy = policy
+	}
+
+	return limits, nil
+}
+
+func parseLimitDateTime(value string, location *time.Location) (time.Time, bool, error) {
+	if parsed, err := time.ParseInLocation("2006-01-02 15:04", value, location); err == nil {
+		return parsed, true, nil
+	}
+
+	parsed, err := time.ParseInLocation("2006-01-02", value, location)
+	return parsed, false, err
+}
+
+// describeNotificationLimits summarizes the remaining occurrences and end date
+// of a notification for /list, or returns an empty string without limits.
+func describeNotificationLimits(notification ScheduledNotification, location *time.Location) string {
+	parts := make([]string, 0, 2)
+
+	if remaining := notification.RemainingOccurrences(); remaining >= 0 {
+		parts = append(parts, fmt.Sprintf("Restantes: %d", remaining))
+	}
+
+	if !notification.EndsAt.IsZero() {
+		parts = append(parts, fmt.Sprintf("Hasta: %s", notification.EndsAt.In(location).Format("2006-01-02 15:04")))
+	}
+
+	return strings.Join(parts, " | ")
+}
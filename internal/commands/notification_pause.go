@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotificationExpired is returned when resuming a notification that has no
+// occurrence left, which is retired instead.
+var ErrNotificationExpired = errors.New("la notificación ya no tiene ocurrencias futuras")
+
+// IsPaused reports whether the notification is paused at the given time. A
+// pause with a ResumeAt ends on its own once that time is reached.
+func (notification ScheduledNotification) IsPaused(at time.Time) bool {
+	return notification.Paused && (notification.ResumeAt.IsZero() || at.Before(notification.ResumeAt))
+}
+
+// pauseNotification silences the notification until resumeAt, or until it is
+// resumed when resumeAt is zero. With a resume time the notification moves
+// straight to its first occurrence from then on, so the occurrences skipped
+// during the pause are never delivered late; it returns errNoMoreOccurrences
+// when nothing is left after resumeAt.
+func pauseNotification(notification *ScheduledNotification, resumeAt, now time.Time) error {
+	notification.Paused = true
+	notification.ResumeAt = resumeAt
+	if resumeAt.IsZero() {
+		return nil
+	}
+
+	return rescheduleNotification(notification, now)
+}
+
+// pauseGuildNotification is the change PauseGuildNotifications applies to
+// each notification. A notification with no occurrence after resumeAt is left
+// as it is and counted in skipped: pausing never retires anything.
+func pauseGuildNotification(resumeAt time.Time, skipped *int) func(notification *ScheduledNotification, now time.Time) (bool, error) {
+	return func(notification *ScheduledNotification, now time.Time) (bool, error) {
+		paused := *notification
+		err := pauseNotification(&paused, resumeAt, now)
+		if errors.Is(err, errNoMoreOccurrences) {
+			*skipped++
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		*notification = paused
+		return true, nil
+	}
+}
+
+// resumeNotification clears a pause and moves the notification to its first
+// occurrence after now, skipping the ones missed while it was paused.
+func resumeNotification(notification *ScheduledNotification, now time.Time) error {
+	if !notification.IsPaused(now) {
+		return errors.New("la notificación no está pausada")
+	}
+
+	notification.Paused = false
+	notification.ResumeAt = time.Time{}
+	return rescheduleNotification(notification, now)
+}
@@ -0,0 +1,459 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// newScheduledNotification completes a notification built from a command
+// input: it validates the limits, takes the guild timezone, fixes the first
+// day of a recurrence rule series and computes the first occurrence after now.
+func newScheduledNotification(notification ScheduledNotification, id, guildID string, config NotificationConfig, now time.Time) (ScheduledNotification, error) {
+	if err := validateNotificationLimits(notification); err != nil {
+		return ScheduledNotification{}, err
+	}
+
+	notification.ID = id
+	notification.GuildID = guildID
+	notification.Timezone = config.Timezone
+
+	if notification.Type == "rrule" && notification.Date == "" {
+		location, err := loadNotificationLocation(config.Timezone)
+		if err != nil {
+			return ScheduledNotification{}, err
+		}
+
+		// The series starts at the first base hour that has not passed yet,
+		// or at the first one after the requested start date.
+		from := now
+		if notification.StartsAt.After(from) {
+			from = notification.StartsAt.Add(-time.Nanosecond)
+		}
+
+		start, err := calculateInitialDailyNextNotificationAt(notification.BaseHour, location, from)
+		if err != nil {
+			return ScheduledNotification{}, err
+		}
+
+		notification.Date = start.In(location).Format("2006-01-02")
+	}
+
+	nextNotificationAt, err := calculateFirstNotificationAt(notification, now)
+	if errors.Is(err, errNoMoreOccurrences) || (err == nil && !nextNotificationAt.After(now)) {
+		return ScheduledNotification{}, errors.New("la notificación no tiene ocurrencias futuras")
+	}
+
+	if err != nil {
+		return ScheduledNotification{}, err
+	}
+
+	notification.NextNotificationAt = nextNotificationAt
+	return notification, nil
+}
+
+func validateNotificationLimits(notification ScheduledNotification) error {
+	if !notification.EndsAt.IsZero() && !notification.StartsAt.IsZero() && !notification.EndsAt.After(notification.StartsAt) {
+		return errors.New("la fecha de fin debe ser posterior a la fecha de inicio")
+	}
+
+	return nil
+}
+
+// advanceAfterDelivery records a delivery at sentAt and moves the notification
+// to its following occurrence. It returns errNoMoreOccurrences when the
+// notification has run out and must be retired.
+func advanceAfterDelivery(notification *ScheduledNotification, sentAt time.Time) error {
+	notification.OccurrencesSent++
+	notification.LastSentAt = sentAt
+
+	// Only notifications whose pause already ended are delivered.
+	notification.Paused = false
+	notification.ResumeAt = time.Time{}
+
+	nextNotificationAt, err := calculateFollowingNotificationAt(*notification, sentAt)
+	if err != nil {
+		return err
+	}
+
+	if nextNotificationAt.After(notification.CatchUpUntil) {
+		notification.CatchUpUntil = time.Time{}
+	}
+
+	notification.NextNotificationAt = nextNotificationAt
+	return nil
+}
+
+// FollowingNotificationAt reports when the notification fires next once the
+// occurrence due now is sent at sentAt, and false when that is its last one.
+func (notification ScheduledNotification) FollowingNotificationAt(sentAt time.Time) (time.Time, bool) {
+	delivered, ok := notification.Delivered(sentAt)
+	return delivered.NextNotificationAt, ok
+}
+
+// Delivered returns the notification as MarkNotificationSent stores it once
+// the occurrence due now is sent at sentAt, and false when that was its last
+// one and the store retires it.
+func (notification ScheduledNotification) Delivered(sentAt time.Time) (ScheduledNotification, bool) {
+	if err := advanceAfterDelivery(&notification, sentAt.UTC()); err != nil {
+		return ScheduledNotification{}, false
+	}
+
+	return notification, true
+}
+
+// startCatchUp points the notification at the earliest missed occurrence its
+// catch-up policy delivers, leaving it untouched when nothing is delivered.
+// Occurrences missed while paused are never caught up.
+func startCatchUp(notification *ScheduledNotification, config NotificationConfig, now time.Time) error {
+	notification.CatchUpUntil = time.Time{}
+	if notification.IsPaused(now) {
+		return nil
+	}
+
+	// A policy that no longer parses, such as a limit stored before limits
+	// were capped, skips what was missed instead of failing every guild.
+	_, keep, err := parseCatchUpPolicy(effectiveCatchUpPolicy(*notification, config))
+	if err != nil {
+		keep = 0
+	}
+
+	missed, err := collectMissedOccurrences(*notification, now, keep)
+	if err != nil {
+		return err
+	}
+
+	if len(missed) > 0 {
+		notification.NextNotificationAt = missed[0]
+		notification.CatchUpUntil = missed[len(missed)-1]
+	}
+
+	return nil
+}
+
+// rescheduleNotification moves the notification to its first occurrence after
+// now, returning errNoMoreOccurrences when there is none.
+func rescheduleNotification(notification *ScheduledNotification, now time.Time) error {
+	notification.CatchUpUntil = time.Time{}
+
+	next, err := calculateFirstNotificationAt(*notification, now)
+	if err != nil {
+		return err
+	}
+
+	notification.NextNotificationAt = next
+	return nil
+}
+
+// calculateFirstNotificationAt returns the next occurrence after now, never
+// earlier than StartsAt or the end of a pause, or errNoMoreOccurrences when
+// the limits are exhausted.
+func calculateFirstNotificationAt(notification ScheduledNotification, now time.Time) (time.Time, error) {
+	from := now
+	if notification.StartsAt.After(from) {
+		from = notification.StartsAt.Add(-time.Nanosecond)
+	}
+
+	if notification.Paused && notification.ResumeAt.After(from) {
+		from = notification.ResumeAt.Add(-time.Nanosecond)
+	}
+
+	next, err := calculateNextFromBaseHour(notification, from)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// A one-shot reminder whose moment already passed has nothing left.
+	if !next.After(from) {
+		return time.Time{}, errNoMoreOccurrences
+	}
+
+	return applyNotificationLimits(notification, next)
+}
+
+// calculateFollowingNotificationAt returns the occurrence after a delivery,
+// or errNoMoreOccurrences when the limits are exhausted. While catching up,
+// the following missed occurrence is returned instead of the next future one.
+func calculateFollowingNotificationAt(notification ScheduledNotification, sentAt time.Time) (time.Time, error) {
+	from := sentAt
+	if notification.IsCatchingUp() && notification.NextNotificationAt.Before(notification.CatchUpUntil) {
+		from = notification.NextNotificationAt
+	}
+
+	next, err := calculateNextNotificationAt(notification, from)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return applyNotificationLimits(notification, next)
+}
+
+func applyNotificationLimits(notification ScheduledNotification, next time.Time) (time.Time, error) {
+	if !notification.hasOccurrencesLeft() {
+		return time.Time{}, errNoMoreOccurrences
+	}
+
+	if !notification.EndsAt.IsZero() && next.After(notification.EndsAt) {
+		return time.Time{}, errNoMoreOccurrences
+	}
+
+	return next, nil
+}
+
+func (notification ScheduledNotification) hasOccurrencesLeft() bool {
+	if notification.MaxOccurrences > 0 && notification.OccurrencesSent >= notification.MaxOccurrences {
+		return false
+	}
+
+	return notification.EndsAt.IsZero() || !notification.NextNotificationAt.After(notification.EndsAt)
+}
+
+// RemainingOccurrences returns how many deliveries are left, or -1 when the
+// notification has no occurrence limit.
+func (notification ScheduledNotification) RemainingOccurrences() int {
+	if notification.MaxOccurrences <= 0 {
+		return -1
+	}
+
+	return max(notification.MaxOccurrences-notification.OccurrencesSent, 0)
+}
+
+func loadNotificationLocation(timezone string) (*time.Location, error) {
+	if strings.TrimSpace(timezone) == "" {
+		return time.UTC, nil
+	}
+
+	if timezone == "Local" {
+		return nil, fmt.Errorf("zona horaria inválida: %s", timezone)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("zona horaria inválida: %s", timezone)
+	}
+
+	return location, nil
+}
+
+func parseBaseHour(baseHour string) (int, int, error) {
+	baseTime, err := time.Parse("15:04", baseHour)
+	if err != nil {
+		return 0, 0, errors.New("el valor base_hour debe tener formato HH:MM (24h)")
+	}
+
+	return baseTime.Hour(), baseTime.Minute(), nil
+}
+
+func calculateInitialNextNotificationAt(baseHour string, everyMinutes int, location *time.Location, now time.Time) (time.Time, error) {
+	if everyMinutes <= 0 {
+		return time.Time{}, errors.New("el valor every_minutes debe ser mayor a 0")
+	}
+
+	hour, minute, err := parseBaseHour(baseHour)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	localNow := now.In(location)
+	next := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), hour, minute, 0, 0, location)
+
+	interval := time.Duration(everyMinutes) * time.Minute
+	for !next.After(now) {
+		next = next.Add(interval)
+	}
+
+	return next.UTC(), nil
+}
+
+// calculateInitialDailyNextNotificationAt walks calendar days in the given
+// location so the notification keeps its wall-clock hour across DST changes.
+func calculateInitialDailyNextNotificationAt(baseHour string, location *time.Location, now time.Time) (time.Time, error) {
+	hour, minute, err := parseBaseHour(baseHour)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	localNow := now.In(location)
+	next := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), hour, minute, 0, 0, location)
+	for days := 1; !next.After(now); days++ {
+		next = time.Date(localNow.Year(), localNow.Month(), localNow.Day()+days, hour, minute, 0, 0, location)
+	}
+
+	return next.UTC(), nil
+}
+
+// calculateInitialWeeklyNextNotificationAt returns the first base hour after
+// now that falls on one of the given weekdays in location.
+func calculateInitialWeeklyNextNotificationAt(baseHour string, weekdays []time.Weekday, location *time.Location, now time.Time) (time.Time, error) {
+	if len(weekdays) == 0 {
+		return time.Time{}, errors.New("la notificación semanal debe tener al menos un día")
+	}
+
+	hour, minute, err := parseBaseHour(baseHour)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	localNow := now.In(location)
+	for days := 0; days <= 7; days++ {
+		next := time.Date(localNow.Year(), localNow.Month(), localNow.Day()+days, hour, minute, 0, 0, location)
+		if next.After(now) && containsWeekday(weekdays, next.Weekday()) {
+			return next.UTC(), nil
+		}
+	}
+
+	return time.Time{}, errors.New("la notificación semanal tiene días inválidos")
+}
+
+// calculateInitialMonthlyNextNotificationAt returns the first base hour after
+// now on the notification's day of the month. Days past the end of a short
+// month fall on its last day, so day 31 fires on 30 April and 28/29 February.
+func calculateInitialMonthlyNextNotificationAt(notification ScheduledNotification, location *time.Location, now time.Time) (time.Time, error) {
+	hour, minute, err := parseBaseHour(notification.BaseHour)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	localNow := now.In(location)
+	for months := 0; months <= 12; months++ {
+		year, month, _ := time.Date(localNow.Year(), localNow.Month()+time.Month(months), 1, 0, 0, 0, 0, location).Date()
+
+		day, err := monthlyNotificationDay(notification, year, month, location)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		next := time.Date(year, month, day, hour, minute, 0, 0, location)
+		if next.After(now) {
+			return next.UTC(), nil
+		}
+	}
+
+	return time.Time{}, errors.New("la notificación mensual no tiene ocurrencias próximas")
+}
+
+func monthlyNotificationDay(notification ScheduledNotification, year int, month time.Month, location *time.Location) (int, error) {
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, location).Day()
+
+	if notification.MonthDay > 0 {
+		if notification.MonthDay > 31 {
+			return 0, errors.New("la notificación mensual tiene un día inválido")
+		}
+
+		return min(notification.MonthDay, daysInMonth), nil
+	}
+
+	if len(notification.Weekdays) != 1 || notification.MonthWeek == 0 || notification.MonthWeek < -1 || notification.MonthWeek > 4 {
+		return 0, errors.New("la notificación mensual tiene una semana o día de la semana inválido")
+	}
+
+	weekday := notification.Weekdays[0]
+	if notification.MonthWeek == -1 {
+		lastWeekday := time.Date(year, month, daysInMonth, 0, 0, 0, 0, location).Weekday()
+		return daysInMonth - (int(lastWeekday)-int(weekday)+7)%7, nil
+	}
+
+	firstWeekday := time.Date(year, month, 1, 0, 0, 0, 0, location).Weekday()
+	return 1 + (int(weekday)-int(firstWeekday)+7)%7 + (notification.MonthWeek-1)*7, nil
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, candidate := range weekdays {
+		if candidate == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+func calculateNextNotificationAt(notification ScheduledNotification, sentAt time.Time) (time.Time, error) {
+	nextNotificationAt := notification.NextNotificationAt
+
+	switch notification.Type {
+	case "daily":
+		location, err := loadNotificationLocation(notification.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return calculateInitialDailyNextNotificationAt(notification.BaseHour, location, sentAt)
+	case "byminutes":
+		if notification.EveryMinutes <= 0 {
+			return time.Time{}, errors.New("la notificación tiene un intervalo inválido")
+		}
+
+		interval := time.Duration(notification.EveryMinutes) * time.Minute
+		for !nextNotificationAt.After(sentAt) {
+			nextNotificationAt = nextNotificationAt.Add(interval)
+		}
+		return nextNotificationAt, nil
+	case "weekly":
+		location, err := loadNotificationLocation(notification.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return calculateInitialWeeklyNextNotificationAt(notification.BaseHour, notification.Weekdays, location, sentAt)
+	case "monthly":
+		location, err := loadNotificationLocation(notification.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return calculateInitialMonthlyNextNotificationAt(notification, location, sentAt)
+	case "cron":
+		return calculateNextCronNotificationAt(notification.CronExpression, notification.Timezone, sentAt)
+	case "rrule":
+		return calculateNextRecurrenceNotificationAt(notification, sentAt)
+	case "once":
+		return time.Time{}, errNoMoreOccurrences
+	default:
+		return time.Time{}, errors.New("tipo de notificación no soportado")
+	}
+}
+
+// notificationDateTime combines Date and BaseHour in the notification timezone.
+func notificationDateTime(notification ScheduledNotification) (time.Time, error) {
+	location, err := loadNotificationLocation(notification.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	at, err := time.ParseInLocation("2006-01-02 15:04", notification.Date+" "+notification.BaseHour, location)
+	if err != nil {
+		return time.Time{}, errors.New("la notificación tiene una fecha u hora inválida")
+	}
+
+	return at.UTC(), nil
+}
+
+func calculateNextFromBaseHour(notification ScheduledNotification, now time.Time) (time.Time, error) {
+	location, err := loadNotificationLocation(notification.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	switch notification.Type {
+	case "daily":
+		return calculateInitialDailyNextNotificationAt(notification.BaseHour, location, now)
+	case "byminutes":
+		return calculateInitialNextNotificationAt(notification.BaseHour, notification.EveryMinutes, location, now)
+	case "weekly":
+		return calculateInitialWeeklyNextNotificationAt(notification.BaseHour, notification.Weekdays, location, now)
+	case "monthly":
+		return calculateInitialMonthlyNextNotificationAt(notification, location, now)
+	case "cron":
+		return calculateNextCronNotificationAt(notification.CronExpression, notification.Timezone, now)
+	case "rrule":
+		return calculateNextRecurrenceNotificationAt(notification, now)
+	case "once":
+		// A reminder missed while the bot was offline keeps its time; whether
+		// it is delivered late or retired unsent depends on the catch-up policy.
+		// Without one, reminders are delivered once on startup.
+		return notificationDateTime(notification)
+	default:
+		return time.Time{}, errors.New("tipo de notificación no soportado")
+	}
+}
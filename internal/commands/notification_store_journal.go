@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cedaesca/alicia/internal/datafile"
+)
+
+const notificationStoreJournalFileName = "notification_store.journal"
+
+// notificationStoreJournal holds the complete new contents of both files of
+// the legacy two-file layout for a mutation that touched the two of them. A
+// journal left by a crash is replayed before the legacy files are migrated.
+type notificationStoreJournal struct {
+	Config        json.RawMessage `json:"config"`
+	Notifications json.RawMessage `json:"notifications"`
+}
+
+// recoverJournal replays a complete journal left by a crash; a journal that
+// cannot be decoded was never committed, so it is discarded and the files
+// keep their previous contents. Temporary files from interrupted atomic
+// writes are removed as well.
+func (store *jsonNotificationConfigStore) recoverJournal() error {
+	if err := datafile.RemoveTemporaryFiles(store.filePath, store.legacyFilePath, store.journalFilePath()); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(store.journalFilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var journal notificationStoreJournal
+	if err := json.Unmarshal(content, &journal); err == nil && len(journal.Config) > 0 && len(journal.Notifications) > 0 {
+		if err := store.applyJournal(journal); err != nil {
+			return fmt.Errorf("replay journal: %w", err)
+		}
+	}
+
+	return datafile.RemoveDurably(store.journalFilePath())
+}
+
+func (store *jsonNotificationConfigStore) applyJournal(journal notificationStoreJournal) error {
+	if err := datafile.WriteAtomic(store.filePath, journal.Config, 0o644); err != nil {
+		return err
+	}
+
+	return datafile.WriteAtomic(store.legacyFilePath, journal.Notifications, 0o644)
+}
+
+func (store *jsonNotificationConfigStore) journalFilePath() string {
+	return filepath.Join(filepath.Dir(store.filePath), notificationStoreJournalFileName)
+}
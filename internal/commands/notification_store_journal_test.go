@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cedaesca/alicia/internal/clock"
+)
+
+func TestJSONNotificationConfigStoreReplaysCommittedJournal(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "notification_config.json")
+	ctx := context.Background()
+
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+	if err := store.SetChannel(ctx, "guild-1", "channel-1"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	// Simulate a crash after the journal was committed but before the
+	// notifications file was replaced.
+	journal := `{"config":{"guilds":{"guild-1":{"channel_id":"channel-2"}}},"notifications":{"notifications":[{"id":"n-1","guild_id":"guild-1","type":"daily","base_hour":"09:00","message":"Hola","next_notification_at":"2024-03-01T09:00:00Z"}]}}`
+	if err := os.WriteFile(filepath.Join(dir, notificationStoreJournalFileName), []byte(journal), 0o644); err != nil {
+		t.Fatalf("failed to prepare journal: %v", err)
+	}
+
+	if _, err := MigrateJSONNotificationStore(filePath, clock.System()); err != nil {
+		t.Fatalf("expected nil error migrating, got %v", err)
+	}
+
+	reopened := NewJSONNotificationConfigStore(filePath, clock.System())
+	config, err := reopened.GetGuildConfig(ctx, "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if config.ChannelID != "channel-2" {
+		t.Fatalf("expected journaled channel, got %q", config.ChannelID)
+	}
+
+	notifications, err := reopened.ListNotifications(ctx)
+	if err != nil || len(notifications) != 1 || notifications[0].ID != "n-1" {
+		t.Fatalf("expected journaled notification, got %+v (%v)", notifications, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, notificationStoreJournalFileName)); !os.IsNotExist(err) {
+		t.Fatalf("expected journal to be removed after replay, got %v", err)
+	}
+}
+
+func TestJSONNotificationConfigStoreDiscardsTornJournalAndTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "notification_config.json")
+	ctx := context.Background()
+
+	store := NewJSONNotificationConfigStore(filePath, clock.System())
+	if err := store.SetChannel(ctx, "guild-1", "channel-1"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	leftovers := []string{
+		filepath.Join(dir, notificationStoreJournalFileName),
+		filePath + ".tmp-123",
+		filepath.Join(dir, "notifications.json.tmp-456"),
+	}
+	for _, path := range leftovers {
+		if err := os.WriteFile(path, []byte(`{"config":{"guil`), 0o644); err != nil {
+			t.Fatalf("failed to prepare %s: %v", path, err)
+		}
+	}
+
+	if _, err := MigrateJSONNotificationStore(filePath, clock.System()); err != nil {
+		t.Fatalf("expected nil error migrating, got %v", err)
+	}
+
+	reopened := NewJSONNotificationConfigStore(filePath, clock.System())
+	config, err := reopened.GetGuildConfig(ctx, "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if config.ChannelID != "channel-1" {
+		t.Fatalf("expected previous channel to be kept, got %q", config.ChannelID)
+	}
+
+	for _, path := range leftovers {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed, got %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/clock"
+	"github.com/cedaesca/alicia/internal/datafile"
+)
+
+// notificationConfigState is the legacy notification_config.json layout, where
+// each guild also kept a copy of its notifications in per-type lists. Only the
+// by-minutes and daily lists were ever released in that layout.
+type notificationConfigState struct {
+	Guilds map[string]legacyNotificationConfig `json:"guilds"`
+}
+
+// legacyNotificationConfig is a guild of the legacy layout: its settings and
+// a definition of each of its notifications, by type.
+type legacyNotificationConfig struct {
+	NotificationConfig
+	ByMinutesNotifications []legacyByMinutesNotification `json:"by_minutes_notifications,omitempty"`
+	DailyNotifications     []legacyDailyNotification     `json:"daily_notifications,omitempty"`
+}
+
+type legacyByMinutesNotification struct {
+	ID           string `json:"id"`
+	EveryMinutes int    `json:"every_minutes"`
+	BaseHour     string `json:"base_hour"`
+	Title        string `json:"title"`
+	Message      string `json:"message"`
+}
+
+type legacyDailyNotification struct {
+	ID       string `json:"id"`
+	BaseHour string `json:"base_hour"`
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+}
+
+// notificationScheduleState is the legacy notifications.json layout.
+type notificationScheduleState struct {
+	Notifications []ScheduledNotification `json:"notifications"`
+}
+
+// MigrateJSONNotificationStore upgrades the store file at filePath to the
+// current schema version and returns a description of every drift between
+// the legacy notification_config.json and notifications.json that was
+// repaired while merging them. It does nothing when the store is current.
+// It must run before the store is opened, which rejects older versions.
+func MigrateJSONNotificationStore(filePath string, clk clock.Clock) ([]string, error) {
+	return newJSONNotificationConfigStore(filePath, clk).migrate()
+}
+
+func (store *jsonNotificationConfigStore) migrate() ([]string, error) {
+	if err := store.recoverJournal(); err != nil {
+		return nil, err
+	}
+
+	// Legacy stores always wrote the config file along with the schedule,
+	// but a schedule alone still needs a config to be merged into.
+	if _, err := os.Stat(store.filePath); errors.Is(err, os.ErrNotExist) {
+		if _, err := os.Stat(store.legacyFilePath); err == nil {
+			if err := datafile.WriteAtomic(store.filePath, []byte("{}"), 0o644); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	repairs := make([]string, 0)
+	registry := datafile.Registry{
+		Current: notificationStoreSchemaVersion,
+		Steps: map[int]datafile.Step{
+			0: func(content []byte) ([]byte, error) {
+				state, stepRepairs, err := store.unifyLegacyFiles(content)
+				repairs = stepRepairs
+				if err != nil {
+					return nil, err
+				}
+
+				return marshalNotificationStoreState(state)
+			},
+		},
+	}
+
+	if _, err := datafile.Migrate(store.filePath, registry); err != nil {
+		return nil, err
+	}
+
+	// Once merged, the legacy schedule is only kept as a backup.
+	if err := datafile.MoveToBackup(store.legacyFilePath, 0); err != nil {
+		return nil, err
+	}
+
+	return repairs, nil
+}
+
+// unifyLegacyFiles is the version 0 to 1 step: it merges the legacy config in
+// content with the schedule kept in notifications.json.
+func (store *jsonNotificationConfigStore) unifyLegacyFiles(content []byte) (notificationStoreState, []string, error) {
+	var configState notificationConfigState
+	if err := json.Unmarshal(content, &configState); err != nil {
+		return notificationStoreState{}, nil, err
+	}
+
+	var scheduleState notificationScheduleState
+	scheduleContent, err := os.ReadFile(store.legacyFilePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return notificationStoreState{}, nil, err
+	default:
+		if err := json.Unmarshal(scheduleContent, &scheduleState); err != nil {
+			return notificationStoreState{}, nil, err
+		}
+	}
+
+	state, repairs := reconcileLegacyState(configState, scheduleState, store.clock.Now().UTC())
+	return state, repairs, nil
+}
+
+// reconcileLegacyState merges the legacy guild config and schedule into the
+// unified state. The schedule copy of a notification wins because it is the
+// one the scheduler delivered and /list showed; config entries missing from
+// the schedule get a fresh schedule, or are dropped when they cannot fire
+// again. Each repaired drift is described in the returned slice.
+func reconcileLegacyState(configState notificationConfigState, scheduleState notificationScheduleState, now time.Time) (notificationStoreState, []string) {
+	state := newNotificationStoreState()
+	repairs := make([]string, 0)
+
+	defined := make(map[string]ScheduledNotification)
+	guildIDs := make([]string, 0, len(configState.Guilds))
+	for guildID, config := range configState.Guilds {
+		state.Guilds[guildID] = config.NotificationConfig
+		guildIDs = append(guildIDs, guildID)
+		for _, notification := range configEntryNotifications(guildID, config) {
+			defined[guildID+"/"+notification.ID] = notification
+		}
+	}
+
+	sort.Strings(guildIDs)
+
+	seen := make(map[string]bool)
+	for _, notification := range scheduleState.Notifications {
+		key := notification.GuildID + "/" + notification.ID
+		if seen[key] {
+			repairs = append(repairs, fmt.Sprintf("notification %s: dropped duplicate schedule entry", key))
+			continue
+		}
+
+		seen[key] = true
+		definition, ok := defined[key]
+		switch {
+		case !ok:
+			repairs = append(repairs, fmt.Sprintf("notification %s: missing from guild config, kept from schedule", key))
+		case !sameNotificationDefinition(definition, notification):
+			repairs = append(repairs, fmt.Sprintf("notification %s: guild config differed from schedule, kept schedule", key))
+		}
+
+		state.Notifications = append(state.Notifications, notification)
+	}
+
+	for _, guildID := range guildIDs {
+		config := configState.Guilds[guildID]
+		for _, definition := range configEntryNotifications(guildID, config) {
+			key := guildID + "/" + definition.ID
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			notification, err := newScheduledNotification(definition, definition.ID, guildID, config.NotificationConfig, now)
+			if err != nil {
+				repairs = append(repairs, fmt.Sprintf("notification %s: missing from schedule and dropped: %v", key, err))
+				continue
+			}
+
+			repairs = append(repairs, fmt.Sprintf("notification %s: missing from schedule, rescheduled", key))
+			state.Notifications = append(state.Notifications, notification)
+		}
+	}
+
+	return state, repairs
+}
+
+// configEntryNotifications returns the definitions kept in the per-type lists
+// of a legacy guild config, with no schedule state.
+func configEntryNotifications(guildID string, config legacyNotificationConfig) []ScheduledNotification {
+	notifications := make([]ScheduledNotification, 0)
+	add := func(id string, notification ScheduledNotification) {
+		notification.ID = id
+		notification.GuildID = guildID
+		notifications = append(notifications, notification)
+	}
+
+	for _, entry := range config.ByMinutesNotifications {
+		add(entry.ID, ByMinutesNotificationInput{EveryMinutes: entry.EveryMinutes, BaseHour: entry.BaseHour, Title: entry.Title, Message: entry.Message}.scheduledNotification())
+	}
+
+	for _, entry := range config.DailyNotifications {
+		add(entry.ID, DailyNotificationInput{BaseHour: entry.BaseHour, Title: entry.Title, Message: entry.Message}.scheduledNotification())
+	}
+
+	return notifications
+}
+
+// sameNotificationDefinition compares the fields a guild config entry keeps,
+// ignoring schedule state.
+func sameNotificationDefinition(a, b ScheduledNotification) bool {
+	return a.Type == b.Type &&
+		a.EveryMinutes == b.EveryMinutes &&
+		a.BaseHour == b.BaseHour &&
+		a.Title == b.Title &&
+		a.Message == b.Message
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/clock"
+)
+
+const legacyNotificationConfigJSON = `{
+  "guilds": {
+    "guild-1": {
+      "channel_id": "channel-1",
+      "role_id": "role-1",
+      "daily_notifications": [
+        {"id": "a", "base_hour": "09:00", "title": "Diario", "message": "Hola"},
+        {"id": "e", "base_hour": "10:00", "title": "Viejo", "message": "Hola"},
+        {"id": "d", "base_hour": "25:00", "title": "Roto", "message": "Hora inválida"}
+      ],
+      "by_minutes_notifications": [
+        {"id": "b", "every_minutes": 30, "base_hour": "09:00", "title": "Cada media hora", "message": "Agua"}
+      ]
+    }
+  }
+}`
+
+const legacyNotificationSchedule = `{
+  "notifications": [
+    {"id": "a", "guild_id": "guild-1", "type": "daily", "every_minutes": 0, "base_hour": "09:00", "title": "Diario", "message": "Hola", "next_notification_at": "2024-03-02T09:00:00Z"},
+    {"id": "a", "guild_id": "guild-1", "type": "daily", "every_minutes": 0, "base_hour": "09:00", "title": "Diario", "message": "Hola", "next_notification_at": "2024-03-02T09:00:00Z"},
+    {"id": "c", "guild_id": "guild-1", "type": "daily", "every_minutes": 0, "base_hour": "08:00", "title": "Huérfano", "message": "Sin config", "next_notification_at": "2024-03-02T08:00:00Z"},
+    {"id": "e", "guild_id": "guild-1", "type": "daily", "every_minutes": 0, "base_hour": "10:00", "title": "Nuevo", "message": "Hola", "next_notification_at": "2024-03-02T10:00:00Z"}
+  ]
+}`
+
+func TestMigrateJSONNotificationStoreRepairsDriftBetweenLegacyFiles(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "notification_config.json")
+	legacyFilePath := filepath.Join(dir, "notifications.json")
+	if err := os.WriteFile(filePath, []byte(legacyNotificationConfigJSON), 0o644); err != nil {
+		t.Fatalf("failed to prepare legacy config: %v", err)
+	}
+
+	if err := os.WriteFile(legacyFilePath, []byte(legacyNotificationSchedule), 0o644); err != nil {
+		t.Fatalf("failed to prepare legacy schedule: %v", err)
+	}
+
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	repairs, err := MigrateJSONNotificationStore(filePath, fakeClock)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedRepairs := []string{
+		"guild-1/a: dropped duplicate",
+		"guild-1/c: missing from guild config",
+		"guild-1/e: guild config differed",
+		"guild-1/b: missing from schedule, rescheduled",
+		"guild-1/d: missing from schedule and dropped",
+	}
+	if len(repairs) != len(expectedRepairs) {
+		t.Fatalf("expected %d repairs, got %q", len(expectedRepairs), repairs)
+	}
+
+	for index, expected := range expectedRepairs {
+		if !strings.Contains(repairs[index], expected) {
+			t.Fatalf("expected repair %d to mention %q, got %q", index, expected, repairs[index])
+		}
+	}
+
+	if _, err := os.Stat(legacyFilePath); !os.IsNotExist(err) {
+		t.Fatalf("expected legacy schedule to be removed, got %v", err)
+	}
+
+	for path, expected := range map[string]string{filePath + ".v0.bak": legacyNotificationConfigJSON, legacyFilePath + ".v0.bak": legacyNotificationSchedule} {
+		backup, err := os.ReadFile(path)
+		if err != nil || string(backup) != expected {
+			t.Fatalf("expected %s to keep the pre-migration content, got %q (%v)", path, backup, err)
+		}
+	}
+
+	store := NewJSONNotificationConfigStore(filePath, fakeClock)
+	notifications, err := store.ListGuildNotifications(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	ids := make([]string, 0, len(notifications))
+	for _, notification := range notifications {
+		ids = append(ids, notification.ID)
+	}
+
+	if strings.Join(ids, ",") != "a,c,e,b" {
+		t.Fatalf("expected notifications a,c,e,b, got %v", ids)
+	}
+
+	if notifications[2].Title != "Nuevo" {
+		t.Fatalf("expected schedule copy to win, got %+v", notifications[2])
+	}
+
+	// 12:30 is the first half hour from 09:00 after the clock.
+	if expected := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC); !notifications[3].NextNotificationAt.Equal(expected) {
+		t.Fatalf("expected rebuilt by-minutes schedule at %v, got %v", expected, notifications[3].NextNotificationAt)
+	}
+
+	config, err := store.GetGuildConfig(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if config != (NotificationConfig{ChannelID: "channel-1", RoleID: "role-1"}) {
+		t.Fatalf("expected only the guild settings to be kept, got %+v", config)
+	}
+}
+
+func TestMigrateJSONNotificationStoreRemovesStaleLegacySchedule(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "notification_config.json")
+	if err := os.WriteFile(filePath, []byte(legacyNotificationConfigJSON), 0o644); err != nil {
+		t.Fatalf("failed to prepare legacy config: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "notifications.json"), []byte(legacyNotificationSchedule), 0o644); err != nil {
+		t.Fatalf("failed to prepare legacy schedule: %v", err)
+	}
+
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := NewJSONNotificationConfigStore(filePath, fakeClock)
+	if _, err := store.ListNotifications(context.Background()); err == nil {
+		t.Fatal("expected the store to reject a legacy file before migrating, got nil")
+	}
+
+	if _, err := MigrateJSONNotificationStore(filePath, fakeClock); err != nil {
+		t.Fatalf("expected nil error migrating, got %v", err)
+	}
+
+	if err := store.DeleteNotification(context.Background(), "guild-1", "c"); err != nil {
+		t.Fatalf("expected nil error deleting migrated notification, got %v", err)
+	}
+
+	// A stale legacy schedule next to the unified file is ignored and removed.
+	if err := os.WriteFile(filepath.Join(dir, "notifications.json"), []byte(legacyNotificationSchedule), 0o644); err != nil {
+		t.Fatalf("failed to prepare stale schedule: %v", err)
+	}
+
+	if repairs, err := MigrateJSONNotificationStore(filePath, clock.System()); err != nil || len(repairs) != 0 {
+		t.Fatalf("expected a current store to need no repairs, got %q (%v)", repairs, err)
+	}
+
+	reopened := NewJSONNotificationConfigStore(filePath, clock.System())
+	notifications, err := reopened.ListNotifications(context.Background())
+	if err != nil || len(notifications) != 3 {
+		t.Fatalf("expected three notifications after delete, got %+v (%v)", notifications, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "notifications.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected stale schedule to be removed, got %v", err)
+	}
+}
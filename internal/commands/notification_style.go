@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+// notificationOptions are the optional options shared by every command that
+// creates or edits a notification: its limits, its target and its embed style.
+func notificationOptions() []discord.SlashCommandOption {
+	options := append(notificationLimitOptions(), notificationTargetOptions()...)
+	return append(options, notificationStyleOptions()...)
+}
+
+func notificationStyleOptions() []discord.SlashCommandOption {
+	return []discord.SlashCommandOption{
+		{
+			Name:        "color",
+			Description: "Color del embed en hexadecimal, por ejemplo #5865F2",
+			Type:        discord.SlashCommandOptionTypeString,
+		},
+		{
+			Name:        "image",
+			Description: "URL de una imagen para el embed",
+			Type:        discord.SlashCommandOptionTypeString,
+		},
+		{
+			Name:        "thumbnail",
+			Description: "URL de una miniatura para el embed",
+			Type:        discord.SlashCommandOptionTypeString,
+		},
+	}
+}
+
+// parseNotificationStyle reads the embed style options. Missing options keep
+// the Discord defaults.
+func parseNotificationStyle(options map[string]string) (NotificationStyle, error) {
+	var style NotificationStyle
+
+	if raw := strings.TrimSpace(options["color"]); raw != "" {
+		color, err := parseEmbedColor(raw)
+		if err != nil {
+			return NotificationStyle{}, err
+		}
+
+		style.Color = color
+	}
+
+	if raw := strings.TrimSpace(options["image"]); raw != "" {
+		imageURL, err := parseEmbedURL("image", raw)
+		if err != nil {
+			return NotificationStyle{}, err
+		}
+
+		style.ImageURL = imageURL
+	}
+
+	if raw := strings.TrimSpace(options["thumbnail"]); raw != "" {
+		thumbnailURL, err := parseEmbedURL("thumbnail", raw)
+		if err != nil {
+			return NotificationStyle{}, err
+		}
+
+		style.ThumbnailURL = thumbnailURL
+	}
+
+	return style, nil
+}
+
+// parseEmbedColor accepts a 24-bit RGB color as six hexadecimal digits, with
+// or without a leading #.
+func parseEmbedColor(value string) (int, error) {
+	digits := strings.TrimPrefix(value, "#")
+	color, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || len(digits) != 6 {
+		return 0, InvalidOptionError("color", "un color hexadecimal como #5865F2")
+	}
+
+	return int(color), nil
+}
+
+func parseEmbedURL(optionName, value string) (string, error) {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", InvalidOptionError(optionName, "una URL http o https")
+	}
+
+	return value, nil
+}
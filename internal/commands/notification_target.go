@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+var (
+	roleMentionPattern = regexp.MustCompile(`^<@&\d+>$`)
+	userMentionPattern = regexp.MustCompile(`^<@!?(\d+)>$`)
+)
+
+func notificationTargetOptions() []discord.SlashCommandOption {
+	return []discord.SlashCommandOption{
+		{
+			Name:        "channel",
+			Description: "Canal de esta notificación, en lugar del canal del servidor",
+			Type:        discord.SlashCommandOptionTypeChannel,
+		},
+		{
+			Name:        "role",
+			Description: "Rol a mencionar en esta notificación, en lugar del rol del servidor",
+			Type:        discord.SlashCommandOptionTypeRole,
+		},
+		{
+			Name:        "mentions",
+			Description: "Menciones de esta notificación: roles, usuarios, @here o @everyone",
+			Type:        discord.SlashCommandOptionTypeString,
+		},
+	}
+}
+
+// parseNotificationTarget reads the channel, role and mention overrides.
+// Missing options keep the guild defaults.
+func parseNotificationTarget(options map[string]string) (NotificationTarget, error) {
+	mentions, err := parseMentionList(options["mentions"])
+	if err != nil {
+		return NotificationTarget{}, err
+	}
+
+	return NotificationTarget{
+		ChannelID: strings.TrimSpace(options["channel"]),
+		RoleID:    strings.TrimSpace(options["role"]),
+		Mentions:  mentions,
+	}, nil
+}
+
+// parseMentionList accepts role and user mentions as Discord writes them,
+// @here and @everyone, separated by spaces or commas, and returns them
+// space-separated without duplicates.
+func parseMentionList(value string) (string, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	mentions := make([]string, 0, len(fields))
+	for _, field := range fields {
+		mention := field
+		switch {
+		case field == "@here" || field == "@everyone" || roleMentionPattern.MatchString(field):
+		case userMentionPattern.MatchString(field):
+			mention = "<@" + userMentionPattern.FindStringSubmatch(field)[1] + ">"
+		default:
+			return "", fmt.Errorf("mención inválida: %q; usa roles, usuarios, @here o @everyone", field)
+		}
+
+		if !slices.Contains(mentions, mention) {
+			mentions = append(mentions, mention)
+		}
+	}
+
+	return strings.Join(mentions, " "), nil
+}
+
+// EffectiveTarget is the channel, role and mention list the notification is
+// delivered to: its own overrides, or the guild configuration for those it
+// does not set.
+func (notification ScheduledNotification) EffectiveTarget(config NotificationConfig) NotificationTarget {
+	target := notification.NotificationTarget
+	if target.ChannelID == "" {
+		target.ChannelID = config.ChannelID
+	}
+
+	if target.RoleID == "" {
+		target.RoleID = config.RoleID
+	}
+
+	if target.Mentions == "" {
+		target.Mentions = config.Mentions
+	}
+
+	return target
+}
+
+// MentionList is everything the target pings: its role followed by its
+// mention list.
+func (target NotificationTarget) MentionList() []string {
+	mentions := make([]string, 0)
+	if target.RoleID != "" {
+		mentions = append(mentions, "<@&"+target.RoleID+">")
+	}
+
+	for _, mention := range strings.Fields(target.Mentions) {
+		if !slices.Contains(mentions, mention) {
+			mentions = append(mentions, mention)
+		}
+	}
+
+	return mentions
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseMentionList(t *testing.T) {
+	mentions, err := parseMentionList(" <@&1>, <@!2>\n@here <@2>  @everyone ")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if mentions != "<@&1> <@2> @here @everyone" {
+		t.Fatalf("unexpected mentions: %q", mentions)
+	}
+
+	for _, value := range []string{"@Raiders", "<@&abc>", "<#123>", "@hereandnow"} {
+		if _, err := parseMentionList(value); err == nil {
+			t.Fatalf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestEffectiveTargetMentionList(t *testing.T) {
+	config := NotificationConfig{ChannelID: "10", RoleID: "20", Mentions: "<@30> @here"}
+
+	inherited := ScheduledNotification{}.EffectiveTarget(config)
+	if mentions := inherited.MentionList(); !slices.Equal(mentions, []string{"<@&20>", "<@30>", "@here"}) {
+		t.Fatalf("expected the guild mentions, got %v", mentions)
+	}
+
+	overridden := ScheduledNotification{NotificationTarget: NotificationTarget{RoleID: "21", Mentions: "<@&20> <@&21>"}}.EffectiveTarget(config)
+	if overridden.ChannelID != "10" {
+		t.Fatalf("expected the guild channel, got %q", overridden.ChannelID)
+	}
+
+	if mentions := overridden.MentionList(); !slices.Equal(mentions, []string{"<@&21>", "<@&20>"}) {
+		t.Fatalf("expected the notification mentions without duplicates, got %v", mentions)
+	}
+
+	if mentions := (NotificationTarget{}).MentionList(); len(mentions) != 0 {
+		t.Fatalf("expected no mentions, got %v", mentions)
+	}
+}
@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Placeholders a notification title or message may contain. Literal braces
+// are written doubled: {{ and }}.
+const (
+	placeholderDate       = "fecha"
+	placeholderTime       = "hora"
+	placeholderWeekday    = "dia_semana"
+	placeholderOccurrence = "ocurrencia"
+	placeholderNext       = "proxima"
+	placeholderRole       = "rol"
+	placeholderChannel    = "canal"
+	// placeholderCountdown takes the target as an argument, either a date or
+	// a date and hour in the notification timezone: {faltan:2024-12-24 18:00}.
+	placeholderCountdown = "faltan"
+)
+
+var spanishWeekdayLongNames = [...]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"}
+
+var spanishMonthNames = [...]string{
+	"enero", "febrero", "marzo", "abril", "mayo", "junio",
+	"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre",
+}
+
+// templateSegment is either literal text or, when placeholder is set, a
+// placeholder with its optional argument.
+type templateSegment struct {
+	literal     string
+	placeholder string
+	argument    string
+}
+
+func parseNotificationTemplate(text string) ([]templateSegment, error) {
+	var segments []templateSegment
+	var literal strings.Builder
+
+	for index := 0; index < len(text); index++ {
+		switch text[index] {
+		case '{':
+			if strings.HasPrefix(text[index:], "{{") {
+				literal.WriteByte('{')
+				index++
+				continue
+			}
+
+			end := strings.IndexAny(text[index+1:], "{}")
+			if end < 0 || text[index+1+end] != '}' {
+				return nil, fmt.Errorf("falta cerrar la llave de %q; usa {{ para escribir {", text[index:])
+			}
+
+			segment, err := parseTemplatePlaceholder(text[index+1 : index+1+end])
+			if err != nil {
+				return nil, err
+			}
+
+			if literal.Len() > 0 {
+				segments = append(segments, templateSegment{literal: literal.String()})
+				literal.Reset()
+			}
+
+			segments = append(segments, segment)
+			index += end + 1
+		case '}':
+			if !strings.HasPrefix(text[index:], "}}") {
+				return nil, errors.New("llave de cierre sin abrir; usa }} para escribir }")
+			}
+
+			literal.WriteByte('}')
+			index++
+		default:
+			literal.WriteByte(text[index])
+		}
+	}
+
+	if literal.Len() > 0 {
+		segments = append(segments, templateSegment{literal: literal.String()})
+	}
+
+	return segments, nil
+}
+
+func parseTemplatePlaceholder(content string) (templateSegment, error) {
+	name, argument, hasArgument := strings.Cut(content, ":")
+	name = strings.TrimSpace(name)
+	argument = strings.TrimSpace(argument)
+
+	switch name {
+	case placeholderDate, placeholderTime, placeholderWeekday, placeholderOccurrence, placeholderNext, placeholderRole, placeholderChannel:
+		if hasArgument {
+			return templateSegment{}, fmt.Errorf("{%s} no admite argumentos", name)
+		}
+	case placeholderCountdown:
+		if _, err := parseCountdownTarget(argument, time.UTC); err != nil {
+			return templateSegment{}, fmt.Errorf("{%s} necesita una fecha AAAA-MM-DD o AAAA-MM-DD HH:MM, por ejemplo {%s:2024-12-24}", name, name)
+		}
+	default:
+		return templateSegment{}, fmt.Errorf("marcador desconocido {%s}", content)
+	}
+
+	return templateSegment{placeholder: name, argument: argument}, nil
+}
+
+func parseCountdownTarget(value string, location *time.Location) (time.Time, error) {
+	if target, err := time.ParseInLocation("2006-01-02 15:04", value, location); err == nil {
+		return target, nil
+	}
+
+	return time.ParseInLocation("2006-01-02", value, location)
+}
+
+// validateNotificationTemplates rejects a title or message whose placeholders
+// would not render.
+func validateNotificationTemplates(title, message string) error {
+	if err := validateNotificationTemplate("title", title); err != nil {
+		return err
+	}
+
+	return validateNotificationTemplate("message", message)
+}
+
+func validateNotificationTemplate(optionName, text string) error {
+	if _, err := parseNotificationTemplate(text); err != nil {
+		return fmt.Errorf("plantilla inválida en %s: %w", optionName, err)
+	}
+
+	return nil
+}
+
+// RenderNotificationTemplates returns the notification with the placeholders
+// in its title and message replaced by their values for the occurrence sent
+// at sentAt, in the notification timezone. Dates, times and countdowns use
+// the scheduled occurrence, so a late or retried delivery shows the moment
+// it was due; {proxima} uses sentAt. Text that is not a valid template, such
+// as a message stored before templates existed, is left as is.
+func RenderNotificationTemplates(notification ScheduledNotification, config NotificationConfig, sentAt time.Time) ScheduledNotification {
+	notification.Title = renderNotificationTemplate(notification.Title, notification, config, sentAt)
+	notification.Message = renderNotificationTemplate(notification.Message, notification, config, sentAt)
+	return notification
+}
+
+func renderNotificationTemplate(text string, notification ScheduledNotification, config NotificationConfig, sentAt time.Time) string {
+	segments, err := parseNotificationTemplate(text)
+	if err != nil {
+		return text
+	}
+
+	location, err := time.LoadLocation(notification.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	occurrence := notification.NextNotificationAt
+	if occurrence.IsZero() {
+		occurrence = sentAt
+	}
+
+	localOccurrence := occurrence.In(location)
+	target := notification.EffectiveTarget(config)
+	var rendered strings.Builder
+	for _, segment := range segments {
+		switch segment.placeholder {
+		case "":
+			rendered.WriteString(segment.literal)
+		case placeholderDate:
+			rendered.WriteString(formatSpanishDate(localOccurrence))
+		case placeholderTime:
+			rendered.WriteString(localOccurrence.Format("15:04"))
+		case placeholderWeekday:
+			rendered.WriteString(spanishWeekdayLongNames[localOccurrence.Weekday()])
+		case placeholderOccurrence:
+			rendered.WriteString(strconv.Itoa(notification.OccurrencesSent + 1))
+		case placeholderNext:
+			if next, ok := notification.FollowingNotificationAt(sentAt); ok {
+				localNext := next.In(location)
+				rendered.WriteString(formatSpanishDate(localNext) + " " + localNext.Format("15:04"))
+			} else {
+				rendered.WriteString("sin próximas")
+			}
+		case placeholderRole:
+			if target.RoleID != "" {
+				rendered.WriteString("<@&" + target.RoleID + ">")
+			}
+		case placeholderChannel:
+			if target.ChannelID != "" {
+				rendered.WriteString("<#" + target.ChannelID + ">")
+			}
+		case placeholderCountdown:
+			target, _ := parseCountdownTarget(segment.argument, location)
+			rendered.WriteString(formatCountdown(target.Sub(occurrence)))
+		}
+	}
+
+	return rendered.String()
+}
+
+func formatSpanishDate(value time.Time) string {
+	return fmt.Sprintf("%d de %s de %d", value.Day(), spanishMonthNames[value.Month()-1], value.Year())
+}
+
+// formatCountdown renders the remaining time in its largest whole unit, so a
+// countdown reads "3 días" until the last day and then switches to hours.
+func formatCountdown(remaining time.Duration) string {
+	switch {
+	case remaining >= 24*time.Hour:
+		return pluralize(int(remaining/(24*time.Hour)), "día", "días")
+	case remaining >= time.Hour:
+		return pluralize(int(remaining/time.Hour), "hora", "horas")
+	default:
+		return pluralize(max(int(remaining/time.Minute), 0), "minuto", "minutos")
+	}
+}
+
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return "1 " + singular
+	}
+
+	return fmt.Sprintf("%d %s", count, plural)
+}
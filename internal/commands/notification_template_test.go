@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateNotificationTemplates(t *testing.T) {
+	valid := []string{
+		"Sin marcadores",
+		"{rol} reunión del {dia_semana} {fecha} a las {hora}",
+		"Edición #{ocurrencia}, la siguiente es el {proxima} en {canal}",
+		"Faltan {faltan:2024-12-24} para Navidad y {faltan: 2024-12-31 23:59} para año nuevo",
+		"Llaves literales: {{rol}} y }}",
+	}
+	for _, template := range valid {
+		if err := validateNotificationTemplates("Título", template); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", template, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"Hola {nombre}":          "plantilla inválida en message: marcador desconocido {nombre}",
+		"Hola {rol":              `plantilla inválida en message: falta cerrar la llave de "{rol"; usa {{ para escribir {`,
+		"Hola {rol {canal}":      `plantilla inválida en message: falta cerrar la llave de "{rol {canal}"; usa {{ para escribir {`,
+		"Hola rol}":              "plantilla inválida en message: llave de cierre sin abrir; usa }} para escribir }",
+		"Hoy es {fecha:largo}":   "plantilla inválida en message: {fecha} no admite argumentos",
+		"Faltan {faltan}":        "plantilla inválida en message: {faltan} necesita una fecha AAAA-MM-DD o AAAA-MM-DD HH:MM, por ejemplo {faltan:2024-12-24}",
+		"Faltan {faltan:mañana}": "plantilla inválida en message: {faltan} necesita una fecha AAAA-MM-DD o AAAA-MM-DD HH:MM, por ejemplo {faltan:2024-12-24}",
+	}
+	for template, expected := range invalid {
+		err := validateNotificationTemplates("Título", template)
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %q for %q, got %v", expected, template, err)
+		}
+	}
+
+	if err := validateNotificationTemplates("{titulo}", "Mensaje"); err == nil || err.Error() != "plantilla inválida en title: marcador desconocido {titulo}" {
+		t.Fatalf("expected invalid title error, got %v", err)
+	}
+}
+
+func TestRenderNotificationTemplates(t *testing.T) {
+	// Wednesday 2024-05-01 09:00 in Caracas.
+	sentAt := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	notification := ScheduledNotification{
+		Type:               "daily",
+		BaseHour:           "09:00",
+		Timezone:           "America/Caracas",
+		Title:              "Reunión #{ocurrencia}",
+		Message:            "{rol} hoy {dia_semana} {fecha} a las {hora} en {canal}. Próxima: {proxima}. Faltan {faltan:2024-05-04}, {faltan:2024-05-01 18:00} y {faltan:2024-05-01 09:30}; {{literal}}",
+		OccurrencesSent:    2,
+		NextNotificationAt: sentAt,
+	}
+	config := NotificationConfig{ChannelID: "channel-1", RoleID: "role-1"}
+
+	rendered := RenderNotificationTemplates(notification, config, sentAt)
+
+	if rendered.Title != "Reunión #3" {
+		t.Fatalf("unexpected title: %q", rendered.Title)
+	}
+
+	expected := "<@&role-1> hoy miércoles 1 de mayo de 2024 a las 09:00 en <#channel-1>. Próxima: 2 de mayo de 2024 09:00. Faltan 2 días, 9 horas y 30 minutos; {literal}"
+	if rendered.Message != expected {
+		t.Fatalf("unexpected message:\n got %q\nwant %q", rendered.Message, expected)
+	}
+
+	if rendered.OccurrencesSent != notification.OccurrencesSent || !rendered.NextNotificationAt.Equal(notification.NextNotificationAt) {
+		t.Fatalf("expected only title and message to change, got %+v", rendered)
+	}
+}
+
+func TestRenderNotificationTemplatesEdgeCases(t *testing.T) {
+	sentAt := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	notification := ScheduledNotification{
+		Type:               "once",
+		Date:               "2024-05-01",
+		BaseHour:           "09:00",
+		Timezone:           "America/Caracas",
+		Message:            "Última: {proxima}. {rol}Pasado: {faltan:2024-04-30}. Mañana: {faltan:2024-05-02 09:00}",
+		NextNotificationAt: sentAt,
+	}
+
+	rendered := RenderNotificationTemplates(notification, NotificationConfig{}, sentAt)
+	if expected := "Última: sin próximas. Pasado: 0 minutos. Mañana: 1 día"; rendered.Message != expected {
+		t.Fatalf("expected %q, got %q", expected, rendered.Message)
+	}
+
+	notification.Message = "Mensaje antiguo con {llaves}"
+	if rendered := RenderNotificationTemplates(notification, NotificationConfig{}, sentAt); rendered.Message != notification.Message {
+		t.Fatalf("expected invalid template to be sent as is, got %q", rendered.Message)
+	}
+}
@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NotificationUpdate lists the fields /edit changes on an existing
+// notification. Nil fields keep their current value; fields that do not apply
+// to the notification type are rejected.
+type NotificationUpdate struct {
+	Title          *string
+	Message        *string
+	BaseHour       *string
+	EveryMinutes   *int
+	Weekdays       []time.Weekday
+	MonthDay       *int
+	MonthWeek      *int
+	Weekday        *time.Weekday
+	CronExpression *string
+	RecurrenceRule *string
+	Date           *string
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+	MaxOccurrences *int
+	CatchUpPolicy  *string
+	ChannelID      *string
+	RoleID         *string
+	Mentions       *string
+	Color          *int
+	ImageURL       *string
+	ThumbnailURL   *string
+}
+
+// IsEmpty reports whether the update changes nothing.
+func (update NotificationUpdate) IsEmpty() bool {
+	return update.Title == nil && update.Message == nil && update.BaseHour == nil &&
+		update.EveryMinutes == nil && update.Weekdays == nil && update.MonthDay == nil &&
+		update.MonthWeek == nil && update.Weekday == nil && update.CronExpression == nil &&
+		update.RecurrenceRule == nil && update.Date == nil && update.StartsAt == nil &&
+		update.EndsAt == nil && update.MaxOccurrences == nil && update.CatchUpPolicy == nil &&
+		update.ChannelID == nil && update.RoleID == nil && update.Mentions == nil &&
+		update.Color == nil && update.ImageURL == nil && update.ThumbnailURL == nil
+}
+
+// changesSchedule reports whether the update touches a field that decides
+// when the notification fires.
+func (update NotificationUpdate) changesSchedule() bool {
+	return update.BaseHour != nil || update.EveryMinutes != nil || update.Weekdays != nil ||
+		update.MonthDay != nil || update.MonthWeek != nil || update.Weekday != nil ||
+		update.CronExpression != nil || update.RecurrenceRule != nil || update.Date != nil ||
+		update.StartsAt != nil || update.EndsAt != nil || update.MaxOccurrences != nil
+}
+
+// updateScheduledNotification applies update to notification. When the
+// schedule changes, the notification moves to its first occurrence after now;
+// otherwise its pending occurrence and catch-up state are kept. The ID,
+// delivery count and last delivery are always kept, so the history of the
+// notification survives the edit.
+func updateScheduledNotification(notification ScheduledNotification, update NotificationUpdate, now time.Time) (ScheduledNotification, error) {
+	if err := applyNotificationUpdate(&notification, update); err != nil {
+		return ScheduledNotification{}, err
+	}
+
+	if err := validateNotificationLimits(notification); err != nil {
+		return ScheduledNotification{}, err
+	}
+
+	if !update.changesSchedule() {
+		return notification, nil
+	}
+
+	// The previous occurrence belongs to the old definition and must not be
+	// checked against the new end date.
+	notification.NextNotificationAt = time.Time{}
+	err := rescheduleNotification(&notification, now)
+	if errors.Is(err, errNoMoreOccurrences) {
+		return ScheduledNotification{}, errors.New("la notificación no tiene ocurrencias futuras")
+	}
+
+	if err != nil {
+		return ScheduledNotification{}, err
+	}
+
+	return notification, nil
+}
+
+func applyNotificationUpdate(notification *ScheduledNotification, update NotificationUpdate) error {
+	if update.Title != nil {
+		notification.Title = *update.Title
+	}
+
+	if update.Message != nil {
+		notification.Message = *update.Message
+	}
+
+	if update.BaseHour != nil {
+		if notification.Type == "cron" {
+			return notApplicableOptionError("base_hour", notification.Type)
+		}
+
+		notification.BaseHour = *update.BaseHour
+	}
+
+	if update.EveryMinutes != nil {
+		if notification.Type != "byminutes" {
+			return notApplicableOptionError("every_minutes", notification.Type)
+		}
+
+		notification.EveryMinutes = *update.EveryMinutes
+	}
+
+	if update.Weekdays != nil {
+		if notification.Type != "weekly" {
+			return notApplicableOptionError("days", notification.Type)
+		}
+
+		notification.Weekdays = update.Weekdays
+	}
+
+	if update.MonthDay != nil || update.MonthWeek != nil || update.Weekday != nil {
+		if notification.Type != "monthly" {
+			return notApplicableOptionError("day/week/weekday", notification.Type)
+		}
+
+		applyMonthlyUpdate(notification, update)
+	}
+
+	if update.CronExpression != nil {
+		if notification.Type != "cron" {
+			return notApplicableOptionError("expression", notification.Type)
+		}
+
+		notification.CronExpression = *update.CronExpression
+	}
+
+	if update.RecurrenceRule != nil {
+		if notification.Type != "rrule" {
+			return notApplicableOptionError("rule", notification.Type)
+		}
+
+		notification.RecurrenceRule = *update.RecurrenceRule
+	}
+
+	if update.Date != nil {
+		if notification.Type != "once" {
+			return notApplicableOptionError("date", notification.Type)
+		}
+
+		notification.Date = *update.Date
+	}
+
+	if update.StartsAt != nil {
+		notification.StartsAt = *update.StartsAt
+	}
+
+	if update.EndsAt != nil {
+		notification.EndsAt = *update.EndsAt
+	}
+
+	if update.MaxOccurrences != nil {
+		notification.MaxOccurrences = *update.MaxOccurrences
+	}
+
+	if update.CatchUpPolicy != nil {
+		notification.CatchUpPolicy = *update.CatchUpPolicy
+	}
+
+	if update.ChannelID != nil {
+		notification.ChannelID = *update.ChannelID
+	}
+
+	if update.RoleID != nil {
+		notification.RoleID = *update.RoleID
+	}
+
+	if update.Mentions != nil {
+		notification.Mentions = *update.Mentions
+	}
+
+	if update.Color != nil {
+		notification.Color = *update.Color
+	}
+
+	if update.ImageURL != nil {
+		notification.ImageURL = *update.ImageURL
+	}
+
+	if update.ThumbnailURL != nil {
+		notification.ThumbnailURL = *update.ThumbnailURL
+	}
+
+	return nil
+}
+
+// applyMonthlyUpdate switches a monthly notification between a day of the
+// month and the Nth weekday of the month; unset fields keep their value.
+func applyMonthlyUpdate(notification *ScheduledNotification, update NotificationUpdate) {
+	if update.MonthDay != nil {
+		notification.MonthDay = *update.MonthDay
+	}
+
+	if update.MonthWeek != nil {
+		notification.MonthWeek = *update.MonthWeek
+	}
+
+	if update.Weekday != nil {
+		notification.Weekdays = []time.Weekday{*update.Weekday}
+	}
+
+	if notification.MonthDay > 0 {
+		notification.MonthWeek = 0
+		notification.Weekdays = nil
+	}
+}
+
+func notApplicableOptionError(optionName, notificationType string) error {
+	return fmt.Errorf("la opción %s no aplica a notificaciones de tipo %s", optionName, notificationType)
+}
@@ -8,6 +8,7 @@ import (
 )
 
 type notificationRoleCommand struct {
+	managerCommand
 	configStore NotificationConfigStore
 }
 
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/clock"
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type pauseCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+	clock       clock.Clock
+}
+
+func NewPauseCommand(configStore NotificationConfigStore, clk clock.Clock) Command {
+	return &pauseCommand{configStore: configStore, clock: clk}
+}
+
+func (command *pauseCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "pause",
+		Description: "Pausa una notificación sin eliminarla",
+		Options: []discord.SlashCommandOption{
+			notificationIDOption(),
+			resumeAtOption(),
+		},
+	}
+}
+
+func (command *pauseCommand) Autocomplete(ctx context.Context, interaction discord.Interaction) ([]discord.AutocompleteChoice, error) {
+	return notificationIDChoices(ctx, command.configStore, interaction)
+}
+
+func (command *pauseCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	notificationID := strings.TrimSpace(interaction.Options["id"])
+	if notificationID == "" {
+		return "", MissingRequiredOptionError("id")
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	resumeAt, err := parseResumeAt(interaction.Options, guildConfig.Timezone, command.clock.Now())
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := command.configStore.PauseNotification(ctx, interaction.GuildID, notificationID, resumeAt); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Notificación pausada%s: %s", describeResumeAt(resumeAt, guildConfig), notificationID), nil
+}
+
+// resumeAtOption is the optional auto-resume date of /pause and /pauseall.
+func resumeAtOption() discord.SlashCommandOption {
+	return discord.SlashCommandOption{
+		Name:        "until",
+		Description: "Reanudar automáticamente, formato AAAA-MM-DD o AAAA-MM-DD HH:MM",
+		Type:        discord.SlashCommandOptionTypeString,
+	}
+}
+
+// parseResumeAt reads the until option in the guild timezone, returning the
+// zero time when the pause has no end. A date without time resumes at 00:00.
+func parseResumeAt(options map[string]string, timezone string, now time.Time) (time.Time, error) {
+	raw := strings.TrimSpace(options["until"])
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	location, err := loadNotificationLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	resumeAt, _, err := parseLimitDateTime(raw, location)
+	if err != nil {
+		return time.Time{}, InvalidOptionError("until", "una fecha AAAA-MM-DD o AAAA-MM-DD HH:MM")
+	}
+
+	if !resumeAt.After(now) {
+		return time.Time{}, errors.New("la fecha de reanudación debe ser futura")
+	}
+
+	return resumeAt.UTC(), nil
+}
+
+func describeResumeAt(resumeAt time.Time, guildConfig NotificationConfig) string {
+	if resumeAt.IsZero() {
+		return ""
+	}
+
+	location, err := loadNotificationLocation(guildConfig.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return fmt.Sprintf(" hasta el %s (%s)", resumeAt.In(location).Format("2006-01-02 15:04"), guildTimezoneName(guildConfig))
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cedaesca/alicia/internal/clock"
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type pauseAllCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+	clock       clock.Clock
+}
+
+func NewPauseAllCommand(configStore NotificationConfigStore, clk clock.Clock) Command {
+	return &pauseAllCommand{configStore: configStore, clock: clk}
+}
+
+func (command *pauseAllCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "pauseall",
+		Description: "Pausa todas las notificaciones del servidor",
+		Options:     []discord.SlashCommandOption{resumeAtOption()},
+	}
+}
+
+func (command *pauseAllCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	resumeAt, err := parseResumeAt(interaction.Options, guildConfig.Timezone, command.clock.Now())
+	if err != nil {
+		return "", err
+	}
+
+	paused, skipped, err := command.configStore.PauseGuildNotifications(ctx, interaction.GuildID, resumeAt)
+	if err != nil {
+		return "", err
+	}
+
+	response := fmt.Sprintf("Notificaciones pausadas%s: %d", describeResumeAt(resumeAt, guildConfig), paused)
+	if skipped > 0 {
+		response += fmt.Sprintf(". Sin pausar, porque no tienen ocurrencias después: %d", skipped)
+	}
+
+	return response, nil
+}
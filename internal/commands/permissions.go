@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+// managerPermissions are required by every command that changes the guild
+// configuration or its notifications.
+const managerPermissions = discord.PermissionManageServer
+
+// RestrictedCommand is implemented by commands that Discord shows to every
+// member but that only some members may run, as checked by Authorize.
+type RestrictedCommand interface {
+	Command
+	RequiredPermissions() int64
+}
+
+// managerCommand is embedded by the commands the guild manager role may use.
+// Discord would hide a command with default member permissions from members
+// who only have that role, so these commands declare none and leave the
+// check to Authorize.
+type managerCommand struct{}
+
+func (managerCommand) RequiredPermissions() int64 {
+	return managerPermissions
+}
+
+// PermissionDeniedError is returned by Authorize when the member may not use
+// the command. Its message is meant to be shown to the member.
+type PermissionDeniedError struct {
+	ManagerRoleID string
+}
+
+func (err *PermissionDeniedError) Error() string {
+	if err.ManagerRoleID == "" {
+		return "No tienes permiso para usar este comando: necesitas el permiso Gestionar servidor"
+	}
+
+	return fmt.Sprintf("No tienes permiso para usar este comando: necesitas el permiso Gestionar servidor o el rol <@&%s>", err.ManagerRoleID)
+}
+
+// Authorize checks the member against the command's default member
+// permissions and, for a RestrictedCommand, its required permissions.
+// Discord already hides commands with default member permissions from most
+// members, but server admins can override that per role or channel, so the
+// bot checks again. Members without the permissions are allowed when they
+// have the guild manager role, except to change the manager role itself.
+func Authorize(ctx context.Context, configStore NotificationConfigStore, command Command, interaction discord.Interaction) error {
+	required := command.Definition().DefaultMemberPermissions
+	if restricted, ok := command.(RestrictedCommand); ok {
+		required |= restricted.RequiredPermissions()
+	}
+
+	if required == 0 || interaction.GuildID == "" {
+		return nil
+	}
+
+	permissions := interaction.MemberPermissions
+	if permissions&discord.PermissionAdministrator != 0 || permissions&required == required {
+		return nil
+	}
+
+	config, err := configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return err
+	}
+
+	if _, isManagerRoleCommand := command.(*managerRoleCommand); !isManagerRoleCommand &&
+		config.ManagerRoleID != "" && slices.Contains(interaction.MemberRoleIDs, config.ManagerRoleID) {
+		return nil
+	}
+
+	return &PermissionDeniedError{ManagerRoleID: config.ManagerRoleID}
+}
@@ -0,0 +1,404 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errNoMoreOccurrences reports that a notification will never fire again and
+// should be retired from the store.
+var errNoMoreOccurrences = errors.New("la notificación no tiene más ocurrencias")
+
+// recurrenceSearchYears bounds the search for the next occurrence so rules that
+// can never match (e.g. BYMONTH=2;BYMONTHDAY=30) terminate.
+const recurrenceSearchYears = 50
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+type recurrenceWeekday struct {
+	weekday time.Weekday
+	nth     int
+}
+
+type recurrenceRule struct {
+	frequency  string
+	interval   int
+	byDay      []recurrenceWeekday
+	byMonthDay []int
+	byMonth    []int
+	bySetPos   []int
+	count      int
+	until      string
+	weekStart  time.Weekday
+}
+
+// parseRecurrenceRule parses the RFC 5545 RRULE subset supported by the bot:
+// FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY, BYMONTHDAY, BYMONTH,
+// BYSETPOS, COUNT, UNTIL and WKST.
+func parseRecurrenceRule(value string) (recurrenceRule, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(value))
+	normalized = strings.TrimPrefix(normalized, "RRULE:")
+	if normalized == "" {
+		return recurrenceRule{}, errors.New("regla RRULE inválida: está vacía")
+	}
+
+	rule := recurrenceRule{interval: 1, weekStart: time.Monday}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(normalized, ";") {
+		name, partValue, ok := strings.Cut(part, "=")
+		if !ok || partValue == "" {
+			return recurrenceRule{}, fmt.Errorf("regla RRULE inválida: parte %q", part)
+		}
+
+		if seen[name] {
+			return recurrenceRule{}, fmt.Errorf("regla RRULE inválida: %s está repetido", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			switch partValue {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				rule.frequency = partValue
+			default:
+				return recurrenceRule{}, fmt.Errorf("regla RRULE inválida: FREQ=%s no está soportado", partValue)
+			}
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(partValue)
+			if err != nil || rule.interval <= 0 {
+				return recurrenceRule{}, fmt.Errorf("regla RRULE inválida: INTERVAL debe ser un entero mayor a 0")
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(partValue)
+			if err != nil || rule.count <= 0 {
+				return recurrenceRule{}, fmt.Errorf("regla RRULE inválida: COUNT debe ser un entero mayor a 0")
+			}
+		case "UNTIL":
+			if _, err := parseRecurrenceUntil(partValue, time.UTC); err != nil {
+				return recurrenceRule{}, err
+			}
+			rule.until = partValue
+		case "BYDAY":
+			if rule.byDay, err = parseRecurrenceByDay(partValue); err != nil {
+				return recurrenceRule{}, err
+			}
+		case "BYMONTHDAY":
+			if rule.byMonthDay, err = parseRecurrenceIntList(name, partValue, 1, 31, true); err != nil {
+				return recurrenceRule{}, err
+			}
+		case "BYMONTH":
+			if rule.byMonth, err = parseRecurrenceIntList(name, partValue, 1, 12, false); err != nil {
+				return recurrenceRule{}, err
+			}
+		case "BYSETPOS":
+			if rule.bySetPos, err = parseRecurrenceIntList(name, partValue, 1, 366, true); err != nil {
+				return recurrenceRule{}, err
+			}
+		case "WKST":
+			weekday, ok := recurrenceWeekdays[partValue]
+			if !ok {
+				return recurrenceRule{}, fmt.Errorf("regla RRULE inválida: WKST=%s", partValue)
+			}
+			rule.weekStart = weekday
+		default:
+			return recurrenceRule{}, fmt.Errorf("regla RRULE inválida: %s no está soportado", name)
+		}
+	}
+
+	if rule.frequency == "" {
+		return recurrenceRule{}, errors.New("regla RRULE inválida: falta FREQ")
+	}
+
+	if rule.count > 0 && rule.until != "" {
+		return recurrenceRule{}, errors.New("regla RRULE inválida: COUNT y UNTIL no pueden usarse juntos")
+	}
+
+	return rule, nil
+}
+
+func parseRecurrenceByDay(value string) ([]recurrenceWeekday, error) {
+	weekdays := make([]recurrenceWeekday, 0)
+	for _, part := range strings.Split(value, ",") {
+		if len(part) < 2 {
+			return nil, fmt.Errorf("regla RRULE inválida: BYDAY=%s", part)
+		}
+
+		weekday, ok := recurrenceWeekdays[part[len(part)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("regla RRULE inválida: BYDAY=%s", part)
+		}
+
+		nth := 0
+		if prefix := part[:len(part)-2]; prefix != "" {
+			parsed, err := strconv.Atoi(prefix)
+			if err != nil || parsed == 0 || parsed < -53 || parsed > 53 {
+				return nil, fmt.Errorf("regla RRULE inválida: BYDAY=%s", part)
+			}
+			nth = parsed
+		}
+
+		weekdays = append(weekdays, recurrenceWeekday{weekday: weekday, nth: nth})
+	}
+
+	return weekdays, nil
+}
+
+func parseRecurrenceIntList(name, value string, min, max int, allowNegative bool) ([]int, error) {
+	values := make([]int, 0)
+	for _, part := range strings.Split(value, ",") {
+		parsed, err := strconv.Atoi(part)
+		absolute := parsed
+		if absolute < 0 && allowNegative {
+			absolute = -absolute
+		}
+
+		if err != nil || absolute < min || absolute > max {
+			return nil, fmt.Errorf("regla RRULE inválida: %s=%s", name, part)
+		}
+
+		values = append(values, parsed)
+	}
+
+	return values, nil
+}
+
+// parseRecurrenceUntil accepts UTC date-times (20270630T220000Z), floating
+// date-times evaluated in location and plain dates, which include the whole day.
+func parseRecurrenceUntil(value string, location *time.Location) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+
+	if until, err := time.ParseInLocation("20060102T150405", value, location); err == nil {
+		return until, nil
+	}
+
+	if until, err := time.ParseInLocation("20060102", value, location); err == nil {
+		return until.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+
+	return time.Time{}, fmt.Errorf("regla RRULE inválida: UNTIL=%s debe tener formato AAAAMMDD o AAAAMMDDTHHMMSSZ", value)
+}
+
+// next returns the first occurrence strictly after the given instant. start is
+// the DTSTART of the series: it sets the time of day, the default weekday or
+// day of month, and the point from which COUNT is counted.
+func (rule recurrenceRule) next(start, after time.Time, location *time.Location) (time.Time, bool) {
+	var until time.Time
+	if rule.until != "" {
+		parsedUntil, err := parseRecurrenceUntil(rule.until, location)
+		if err != nil {
+			return time.Time{}, false
+		}
+		until = parsedUntil
+	}
+
+	localStart := start.In(location)
+	limitYear := after.In(location).Year() + recurrenceSearchYears
+	occurrences := 0
+
+	for period := 0; ; period++ {
+		periodStart, periodEnd := rule.periodRange(localStart, period)
+		if periodStart.Year() > limitYear {
+			return time.Time{}, false
+		}
+
+		for _, day := range rule.expandPeriod(localStart, periodStart, periodEnd) {
+			occurrence := time.Date(day.Year(), day.Month(), day.Day(), localStart.Hour(), localStart.Minute(), 0, 0, location)
+			if occurrence.Before(start) {
+				continue
+			}
+
+			occurrences++
+			if rule.count > 0 && occurrences > rule.count {
+				return time.Time{}, false
+			}
+
+			if !until.IsZero() && occurrence.After(until) {
+				return time.Time{}, false
+			}
+
+			if occurrence.After(after) {
+				return occurrence, true
+			}
+		}
+	}
+}
+
+// periodRange returns the [start, end) range of calendar days covered by the
+// given FREQ/INTERVAL period, counted from the DTSTART period.
+func (rule recurrenceRule) periodRange(localStart time.Time, period int) (time.Time, time.Time) {
+	location := localStart.Location()
+	year, month, day := localStart.Date()
+	step := period * rule.interval
+
+	switch rule.frequency {
+	case "WEEKLY":
+		offset := (int(localStart.Weekday()) - int(rule.weekStart) + 7) % 7
+		weekStart := time.Date(year, month, day-offset+step*7, 0, 0, 0, 0, location)
+		return weekStart, weekStart.AddDate(0, 0, 7)
+	case "MONTHLY":
+		monthStart := time.Date(year, month+time.Month(step), 1, 0, 0, 0, 0, location)
+		return monthStart, monthStart.AddDate(0, 1, 0)
+	case "YEARLY":
+		yearStart := time.Date(year+step, time.January, 1, 0, 0, 0, 0, location)
+		return yearStart, yearStart.AddDate(1, 0, 0)
+	default:
+		dayStart := time.Date(year, month, day+step, 0, 0, 0, 0, location)
+		return dayStart, dayStart.AddDate(0, 0, 1)
+	}
+}
+
+func (rule recurrenceRule) expandPeriod(localStart, periodStart, periodEnd time.Time) []time.Time {
+	days := make([]time.Time, 0)
+	for day := periodStart; day.Before(periodEnd); day = day.AddDate(0, 0, 1) {
+		if rule.matchesDay(localStart, day) {
+			days = append(days, day)
+		}
+	}
+
+	if len(rule.bySetPos) == 0 {
+		return days
+	}
+
+	selected := make([]time.Time, 0, len(rule.bySetPos))
+	for _, position := range rule.bySetPos {
+		index := position - 1
+		if position < 0 {
+			index = len(days) + position
+		}
+
+		if index >= 0 && index < len(days) {
+			selected = append(selected, days[index])
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Before(selected[j])
+	})
+
+	unique := make([]time.Time, 0, len(selected))
+	for _, day := range selected {
+		if len(unique) == 0 || !unique[len(unique)-1].Equal(day) {
+			unique = append(unique, day)
+		}
+	}
+
+	return unique
+}
+
+func (rule recurrenceRule) matchesDay(localStart, day time.Time) bool {
+	if len(rule.byMonth) > 0 && !containsInt(rule.byMonth, int(day.Month())) {
+		return false
+	}
+
+	if len(rule.byMonthDay) > 0 && !rule.matchesMonthDay(day) {
+		return false
+	}
+
+	if len(rule.byDay) > 0 && !rule.matchesWeekday(day) {
+		return false
+	}
+
+	// Without BYxxx parts the series repeats on the DTSTART weekday/day/date.
+	if len(rule.byMonthDay) > 0 || len(rule.byDay) > 0 {
+		return true
+	}
+
+	switch rule.frequency {
+	case "WEEKLY":
+		return day.Weekday() == localStart.Weekday()
+	case "MONTHLY":
+		return day.Day() == localStart.Day()
+	case "YEARLY":
+		if len(rule.byMonth) > 0 {
+			return day.Day() == localStart.Day()
+		}
+
+		return day.Month() == localStart.Month() && day.Day() == localStart.Day()
+	default:
+		return true
+	}
+}
+
+func (rule recurrenceRule) matchesMonthDay(day time.Time) bool {
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	for _, monthDay := range rule.byMonthDay {
+		if monthDay == day.Day() || (monthDay < 0 && daysInMonth+monthDay+1 == day.Day()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rule recurrenceRule) matchesWeekday(day time.Time) bool {
+	// Ordinals such as 2TU or -1FR are relative to the month, except for
+	// yearly rules without BYMONTH where they are relative to the year.
+	position := day.Day()
+	scopeLength := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	if rule.frequency == "YEARLY" && len(rule.byMonth) == 0 {
+		position = day.YearDay()
+		scopeLength = time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, day.Location()).YearDay()
+	}
+
+	for _, weekday := range rule.byDay {
+		if weekday.weekday != day.Weekday() {
+			continue
+		}
+
+		switch {
+		case weekday.nth == 0:
+			return true
+		case weekday.nth > 0 && (position-1)/7+1 == weekday.nth:
+			return true
+		case weekday.nth < 0 && (scopeLength-position)/7+1 == -weekday.nth:
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func calculateNextRecurrenceNotificationAt(notification ScheduledNotification, after time.Time) (time.Time, error) {
+	rule, err := parseRecurrenceRule(notification.RecurrenceRule)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	start, err := notificationDateTime(notification)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location, err := loadNotificationLocation(notification.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next, ok := rule.next(start, after, location)
+	if !ok {
+		return time.Time{}, errNoMoreOccurrences
+	}
+
+	return next.UTC(), nil
+}
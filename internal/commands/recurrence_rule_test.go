@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRuleRejectsInvalidRules(t *testing.T) {
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20270101",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;UNTIL=2027-01-01",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYHOUR=3",
+	}
+
+	for _, rule := range invalid {
+		if _, err := parseRecurrenceRule(rule); err == nil {
+			t.Fatalf("expected error for %q, got nil", rule)
+		}
+	}
+}
+
+func collectRecurrenceOccurrences(t *testing.T, value string, start time.Time, limit int) []time.Time {
+	t.Helper()
+
+	rule, err := parseRecurrenceRule(value)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	occurrences := make([]time.Time, 0)
+	after := start.Add(-time.Nanosecond)
+	for len(occurrences) < limit {
+		next, ok := rule.next(start, after, start.Location())
+		if !ok {
+			break
+		}
+
+		occurrences = append(occurrences, next)
+		after = next
+	}
+
+	return occurrences
+}
+
+func assertRecurrenceDates(t *testing.T, occurrences []time.Time, expected []string) {
+	t.Helper()
+
+	if len(occurrences) != len(expected) {
+		t.Fatalf("expected %d occurrences, got %d: %v", len(expected), len(occurrences), occurrences)
+	}
+
+	for index, occurrence := range occurrences {
+		if occurrence.Format("2006-01-02 15:04") != expected[index] {
+			t.Fatalf("occurrence %d: expected %s, got %s", index, expected[index], occurrence.Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	location, err := loadNotificationLocation("America/Caracas")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	// 2026-10-20 is a Tuesday.
+	start := time.Date(2026, 10, 20, 18, 0, 0, 0, location)
+
+	t.Run("every two weeks on tuesday and thursday until a date", func(t *testing.T) {
+		occurrences := collectRecurrenceOccurrences(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20261118", start, 10)
+		assertRecurrenceDates(t, occurrences, []string{
+			"2026-10-20 18:00",
+			"2026-10-22 18:00",
+			"2026-11-03 18:00",
+			"2026-11-05 18:00",
+			"2026-11-17 18:00",
+		})
+	})
+
+	t.Run("last friday of every month ten times", func(t *testing.T) {
+		occurrences := collectRecurrenceOccurrences(t, "FREQ=MONTHLY;BYDAY=-1FR;COUNT=10", start, 20)
+		if len(occurrences) != 10 {
+			t.Fatalf("expected 10 occurrences, got %d", len(occurrences))
+		}
+
+		assertRecurrenceDates(t, occurrences[:3], []string{
+			"2026-10-30 18:00",
+			"2026-11-27 18:00",
+			"2026-12-25 18:00",
+		})
+	})
+
+	t.Run("last weekday of the month with bysetpos", func(t *testing.T) {
+		occurrences := collectRecurrenceOccurrences(t, "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", start, 3)
+		assertRecurrenceDates(t, occurrences, []string{
+			"2026-10-30 18:00",
+			"2026-11-30 18:00",
+			"2026-12-31 18:00",
+		})
+	})
+
+	t.Run("monthly by negative month day", func(t *testing.T) {
+		occurrences := collectRecurrenceOccurrences(t, "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3", start, 5)
+		assertRecurrenceDates(t, occurrences, []string{
+			"2026-10-31 18:00",
+			"2026-11-30 18:00",
+			"2026-12-31 18:00",
+		})
+	})
+
+	t.Run("daily with interval and count", func(t *testing.T) {
+		occurrences := collectRecurrenceOccurrences(t, "FREQ=DAILY;INTERVAL=3;COUNT=3", start, 5)
+		assertRecurrenceDates(t, occurrences, []string{
+			"2026-10-20 18:00",
+			"2026-10-23 18:00",
+			"2026-10-26 18:00",
+		})
+	})
+
+	t.Run("yearly defaults to dtstart date", func(t *testing.T) {
+		occurrences := collectRecurrenceOccurrences(t, "FREQ=YEARLY", start, 2)
+		assertRecurrenceDates(t, occurrences, []string{
+			"2026-10-20 18:00",
+			"2027-10-20 18:00",
+		})
+	})
+
+	t.Run("impossible rule terminates", func(t *testing.T) {
+		occurrences := collectRecurrenceOccurrences(t, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", start, 1)
+		if len(occurrences) != 0 {
+			t.Fatalf("expected no occurrences, got %v", occurrences)
+		}
+	})
+}
+
+func TestCalculateNextRecurrenceNotificationAtReportsExhaustion(t *testing.T) {
+	notification := ScheduledNotification{
+		Type:           "rrule",
+		RecurrenceRule: "FREQ=DAILY;COUNT=2",
+		BaseHour:       "09:00",
+		Date:           "2026-10-20",
+	}
+
+	_, err := calculateNextRecurrenceNotificationAt(notification, time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC))
+	if !errors.Is(err, errNoMoreOccurrences) {
+		t.Fatalf("expected %v, got %v", errNoMoreOccurrences, err)
+	}
+}
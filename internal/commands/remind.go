@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/clock"
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+var reminderDateLayouts = []string{"2006-01-02", "02/01/2006"}
+
+type remindCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+	clock       clock.Clock
+}
+
+func NewRemindCommand(configStore NotificationConfigStore, clk clock.Clock) Command {
+	return &remindCommand{configStore: configStore, clock: clk}
+}
+
+func (command *remindCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "remind",
+		Description: "Crea un recordatorio que se envía una sola vez",
+		Options: append([]discord.SlashCommandOption{
+			{
+				Name:        "date",
+				Description: "Fecha del recordatorio, formato AAAA-MM-DD o DD/MM/AAAA",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "time",
+				Description: "Hora en la zona horaria del servidor, formato HH:MM (24h)",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "title",
+				Description: "Título del recordatorio",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "message",
+				Description: "Mensaje del recordatorio; admite marcadores como {fecha}, {rol} o {faltan:AAAA-MM-DD}",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+		}, notificationOptions()...),
+	}
+}
+
+func (command *remindCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	dateRaw := strings.TrimSpace(interaction.Options["date"])
+	if dateRaw == "" {
+		return "", MissingRequiredOptionError("date")
+	}
+
+	baseHour := strings.TrimSpace(interaction.Options["time"])
+	if baseHour == "" {
+		return "", MissingRequiredOptionError("time")
+	}
+
+	title := strings.TrimSpace(interaction.Options["title"])
+	if title == "" {
+		return "", MissingRequiredOptionError("title")
+	}
+
+	message := strings.TrimSpace(interaction.Options["message"])
+	if message == "" {
+		return "", MissingRequiredOptionError("message")
+	}
+
+	if err := validateNotificationTemplates(title, message); err != nil {
+		return "", err
+	}
+
+	date, err := parseReminderDate(dateRaw)
+	if err != nil {
+		return "", err
+	}
+
+	if _, _, err := parseBaseHour(baseHour); err != nil {
+		return "", errors.New("el valor time debe tener formato HH:MM (24h)")
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	limits, err := parseNotificationLimits(interaction.Options, guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	style, err := parseNotificationStyle(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := parseNotificationTarget(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	at, err := notificationDateTime(ScheduledNotification{Date: date, BaseHour: baseHour, Timezone: guildConfig.Timezone})
+	if err != nil {
+		return "", err
+	}
+
+	if !at.After(command.clock.Now()) {
+		return "", errors.New("la fecha y hora del recordatorio ya pasaron")
+	}
+
+	id, err := command.configStore.AddOnceNotification(ctx, interaction.GuildID, OnceNotificationInput{
+		Date:               date,
+		BaseHour:           baseHour,
+		Title:              title,
+		Message:            message,
+		NotificationLimits: limits,
+		NotificationStyle:  style,
+		NotificationTarget: target,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Recordatorio creado para el %s a las %s (%s). ID: %s", date, baseHour, guildTimezoneName(guildConfig), id), nil
+}
+
+func parseReminderDate(value string) (string, error) {
+	for _, layout := range reminderDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.Format("2006-01-02"), nil
+		}
+	}
+
+	return "", errors.New("el valor date debe tener formato AAAA-MM-DD o DD/MM/AAAA")
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type resumeCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewResumeCommand(configStore NotificationConfigStore) Command {
+	return &resumeCommand{configStore: configStore}
+}
+
+func (command *resumeCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "resume",
+		Description: "Reanuda una notificación pausada",
+		Options:     []discord.SlashCommandOption{notificationIDOption()},
+	}
+}
+
+func (command *resumeCommand) Autocomplete(ctx context.Context, interaction discord.Interaction) ([]discord.AutocompleteChoice, error) {
+	return notificationIDChoices(ctx, command.configStore, interaction)
+}
+
+func (command *resumeCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	notificationID := strings.TrimSpace(interaction.Options["id"])
+	if notificationID == "" {
+		return "", MissingRequiredOptionError("id")
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	notification, err := command.configStore.ResumeNotification(ctx, interaction.GuildID, notificationID)
+	if errors.Is(err, ErrNotificationExpired) {
+		return fmt.Sprintf("La notificación %s ya no tenía ocurrencias futuras y se eliminó", notificationID), nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	location, err := loadNotificationLocation(guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	nextAt := notification.NextNotificationAt.In(location).Format("2006-01-02 15:04")
+	return fmt.Sprintf("Notificación reanudada: %s. Próxima: %s (%s)", notificationID, nextAt, guildTimezoneName(guildConfig)), nil
+}
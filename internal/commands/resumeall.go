@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type resumeAllCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewResumeAllCommand(configStore NotificationConfigStore) Command {
+	return &resumeAllCommand{configStore: configStore}
+}
+
+func (command *resumeAllCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "resumeall",
+		Description: "Reanuda todas las notificaciones pausadas del servidor",
+	}
+}
+
+func (command *resumeAllCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	resumed, err := command.configStore.ResumeGuildNotifications(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Notificaciones reanudadas: %d", resumed), nil
+}
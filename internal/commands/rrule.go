@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type rruleCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewRRuleCommand(configStore NotificationConfigStore) Command {
+	return &rruleCommand{configStore: configStore}
+}
+
+func (command *rruleCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "rrule",
+		Description: "Crea una notificación a partir de una regla RRULE (RFC 5545)",
+		Options: append([]discord.SlashCommandOption{
+			{
+				Name:        "rule",
+				Description: "Regla RRULE, por ejemplo FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "base_hour",
+				Description: "Hora base en la zona horaria del servidor, formato HH:MM (24h)",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "title",
+				Description: "Título de la notificación",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "message",
+				Description: "Mensaje de la notificación; admite marcadores como {fecha}, {rol} o {faltan:AAAA-MM-DD}",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+		}, notificationOptions()...),
+	}
+}
+
+func (command *rruleCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	rule := strings.ToUpper(strings.TrimSpace(interaction.Options["rule"]))
+	if rule == "" {
+		return "", MissingRequiredOptionError("rule")
+	}
+
+	baseHour := interaction.Options["base_hour"]
+	if baseHour == "" {
+		return "", MissingRequiredOptionError("base_hour")
+	}
+
+	title := strings.TrimSpace(interaction.Options["title"])
+	if title == "" {
+		return "", MissingRequiredOptionError("title")
+	}
+
+	message := strings.TrimSpace(interaction.Options["message"])
+	if message == "" {
+		return "", MissingRequiredOptionError("message")
+	}
+
+	if err := validateNotificationTemplates(title, message); err != nil {
+		return "", err
+	}
+
+	rule = strings.TrimPrefix(rule, "RRULE:")
+	if _, err := parseRecurrenceRule(rule); err != nil {
+		return "", err
+	}
+
+	if _, _, err := parseBaseHour(baseHour); err != nil {
+		return "", err
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	limits, err := parseNotificationLimits(interaction.Options, guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	style, err := parseNotificationStyle(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := parseNotificationTarget(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := command.configStore.AddRecurrenceNotification(ctx, interaction.GuildID, RecurrenceNotificationInput{
+		RecurrenceRule:     rule,
+		BaseHour:           baseHour,
+		Title:              title,
+		Message:            message,
+		NotificationLimits: limits,
+		NotificationStyle:  style,
+		NotificationTarget: target,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Notificación creada correctamente (hora base en %s). ID: %s", guildTimezoneName(guildConfig), id), nil
+}
@@ -8,6 +8,7 @@ import (
 )
 
 type setChannelCommand struct {
+	managerCommand
 	configStore   NotificationConfigStore
 	messageSender MessageSender
 }
@@ -42,7 +43,7 @@ func (command *setChannelCommand) Execute(ctx context.Context, interaction disco
 	}
 
 	if command.messageSender != nil {
-		if err := command.messageSender.SendMessage(channelID, "✅ Canal de notificaciones verificado."); err != nil {
+		if err := command.messageSender.SendMessage(channelID, "✅ Canal de notificaciones verificado.", discord.AllowedMentions{}); err != nil {
 			return "", fmt.Errorf("no tengo acceso al canal seleccionado; verifica permisos y que el bot esté en el servidor")
 		}
 	}
@@ -0,0 +1,742 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/clock"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS guild_configs (
+	guild_id        TEXT PRIMARY KEY,
+	channel_id      TEXT NOT NULL DEFAULT '',
+	role_id         TEXT NOT NULL DEFAULT '',
+	timezone        TEXT NOT NULL DEFAULT '',
+	catch_up_policy TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+	id                   TEXT PRIMARY KEY,
+	guild_id             TEXT NOT NULL,
+	type                 TEXT NOT NULL,
+	every_minutes        INTEGER NOT NULL DEFAULT 0,
+	base_hour            TEXT NOT NULL DEFAULT '',
+	timezone             TEXT NOT NULL DEFAULT '',
+	weekdays             TEXT NOT NULL DEFAULT '',
+	month_day            INTEGER NOT NULL DEFAULT 0,
+	month_week           INTEGER NOT NULL DEFAULT 0,
+	cron_expression      TEXT NOT NULL DEFAULT '',
+	recurrence_rule      TEXT NOT NULL DEFAULT '',
+	date                 TEXT NOT NULL DEFAULT '',
+	title                TEXT NOT NULL,
+	message              TEXT NOT NULL,
+	starts_at            INTEGER,
+	ends_at              INTEGER,
+	max_occurrences      INTEGER NOT NULL DEFAULT 0,
+	catch_up_policy      TEXT NOT NULL DEFAULT '',
+	occurrences_sent     INTEGER NOT NULL DEFAULT 0,
+	last_sent_at         INTEGER,
+	catch_up_until       INTEGER,
+	next_notification_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS notifications_next_notification_at ON notifications (next_notification_at);
+CREATE INDEX IF NOT EXISTS notifications_guild_id ON notifications (guild_id);
+`
+
+// sqliteMigrations upgrade the tables created by sqliteSchema. Entry i moves
+// the database from user_version i to i+1, so columns added after the first
+// release reach existing databases as well as new ones.
+var sqliteMigrations = []string{
+	`ALTER TABLE notifications ADD COLUMN paused INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE notifications ADD COLUMN resume_at INTEGER;`,
+	`ALTER TABLE notifications ADD COLUMN color INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE notifications ADD COLUMN image_url TEXT NOT NULL DEFAULT '';
+	ALTER TABLE notifications ADD COLUMN thumbnail_url TEXT NOT NULL DEFAULT '';
+	ALTER TABLE guild_configs ADD COLUMN plain_text INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE notifications ADD COLUMN channel_id TEXT NOT NULL DEFAULT '';
+	ALTER TABLE notifications ADD COLUMN role_id TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE notifications ADD COLUMN mentions TEXT NOT NULL DEFAULT '';
+	ALTER TABLE guild_configs ADD COLUMN mentions TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE guild_configs ADD COLUMN manager_role_id TEXT NOT NULL DEFAULT '';`,
+}
+
+const sqliteNotificationColumns = `id, guild_id, type, every_minutes, base_hour, timezone, weekdays,
+	month_day, month_week, cron_expression, recurrence_rule, date, title, message,
+	starts_at, ends_at, max_occurrences, catch_up_policy, color, image_url, thumbnail_url,
+	channel_id, role_id, mentions, paused, resume_at, occurrences_sent, last_sent_at, catch_up_until, next_notification_at`
+
+// sqlQuerier is implemented by both *sql.DB and *sql.Tx.
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SQLiteNotificationConfigStore keeps guild configs and scheduled
+// notifications in an embedded SQLite database. Timestamps are stored as Unix
+// nanoseconds so that due notifications are found through an index.
+type SQLiteNotificationConfigStore struct {
+	db    *sql.DB
+	clock clock.Clock
+	newID func() (string, error)
+}
+
+// maxNotificationIDAttempts bounds how many random IDs are drawn for a new
+// notification before giving up on collisions.
+const maxNotificationIDAttempts = 5
+
+func NewSQLiteNotificationConfigStore(filePath string, clk clock.Clock) (*SQLiteNotificationConfigStore, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", "file:"+filePath+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+
+	// A single connection serializes writers, matching SQLite's own locking.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create sqlite schema: %w", err)
+	}
+
+	if err := migrateSQLiteSchema(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrate sqlite schema: %w", err)
+	}
+
+	return &SQLiteNotificationConfigStore{db: db, clock: clk, newID: generateShortID}, nil
+}
+
+func (store *SQLiteNotificationConfigStore) Close() error {
+	return store.db.Close()
+}
+
+// migrateSQLiteSchema applies the migrations newer than the database
+// user_version, each in its own transaction.
+func migrateSQLiteSchema(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("unsupported sqlite schema version %d", version)
+	}
+
+	for ; version < len(sqliteMigrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(sqliteMigrations[version]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("version %d: %w", version+1, err)
+		}
+
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (store *SQLiteNotificationConfigStore) SetChannel(ctx context.Context, guildID, channelID string) error {
+	return store.setGuildColumn(ctx, guildID, "channel_id", channelID)
+}
+
+func (store *SQLiteNotificationConfigStore) SetRole(ctx context.Context, guildID, roleID string) error {
+	return store.setGuildColumn(ctx, guildID, "role_id", roleID)
+}
+
+func (store *SQLiteNotificationConfigStore) SetCatchUpPolicy(ctx context.Context, guildID, policy string) error {
+	normalized, _, err := parseCatchUpPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	return store.setGuildColumn(ctx, guildID, "catch_up_policy", normalized)
+}
+
+func (store *SQLiteNotificationConfigStore) SetPlainText(ctx context.Context, guildID string, enabled bool) error {
+	return store.setGuildColumn(ctx, guildID, "plain_text", enabled)
+}
+
+func (store *SQLiteNotificationConfigStore) SetMentions(ctx context.Context, guildID, mentions string) error {
+	normalized, err := parseMentionList(mentions)
+	if err != nil {
+		return err
+	}
+
+	return store.setGuildColumn(ctx, guildID, "mentions", normalized)
+}
+
+func (store *SQLiteNotificationConfigStore) SetManagerRole(ctx context.Context, guildID, roleID string) error {
+	return store.setGuildColumn(ctx, guildID, "manager_role_id", roleID)
+}
+
+func (store *SQLiteNotificationConfigStore) SetTimezone(ctx context.Context, guildID, timezone string) error {
+	if _, err := loadNotificationLocation(timezone); err != nil {
+		return err
+	}
+
+	return store.inTransaction(ctx, func(tx *sql.Tx) error {
+		if err := upsertGuildColumn(ctx, tx, guildID, "timezone", timezone); err != nil {
+			return err
+		}
+
+		notifications, err := queryNotifications(ctx, tx, "WHERE guild_id = ?", guildID)
+		if err != nil {
+			return err
+		}
+
+		now := store.clock.Now().UTC()
+		for _, notification := range notifications {
+			notification.Timezone = timezone
+			if err := rescheduleNotification(&notification, now); err != nil {
+				if errors.Is(err, errNoMoreOccurrences) {
+					err = deleteNotificationRow(ctx, tx, notification.ID)
+				}
+
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := updateNotificationRow(ctx, tx, notification); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (store *SQLiteNotificationConfigStore) AddByMinutesNotification(ctx context.Context, guildID string, input ByMinutesNotificationInput) (string, error) {
+	return store.addNotification(ctx, guildID, input.scheduledNotification())
+}
+
+func (store *SQLiteNotificationConfigStore) AddDailyNotification(ctx context.Context, guildID string, input DailyNotificationInput) (string, error) {
+	return store.addNotification(ctx, guildID, input.scheduledNotification())
+}
+
+func (store *SQLiteNotificationConfigStore) AddWeeklyNotification(ctx context.Context, guildID string, input WeeklyNotificationInput) (string, error) {
+	return store.addNotification(ctx, guildID, input.scheduledNotification())
+}
+
+func (store *SQLiteNotificationConfigStore) AddMonthlyNotification(ctx context.Context, guildID string, input MonthlyNotificationInput) (string, error) {
+	return store.addNotification(ctx, guildID, input.scheduledNotification())
+}
+
+func (store *SQLiteNotificationConfigStore) AddCronNotification(ctx context.Context, guildID string, input CronNotificationInput) (string, error) {
+	return store.addNotification(ctx, guildID, input.scheduledNotification())
+}
+
+func (store *SQLiteNotificationConfigStore) AddRecurrenceNotification(ctx context.Context, guildID string, input RecurrenceNotificationInput) (string, error) {
+	return store.addNotification(ctx, guildID, input.scheduledNotification())
+}
+
+func (store *SQLiteNotificationConfigStore) AddOnceNotification(ctx context.Context, guildID string, input OnceNotificationInput) (string, error) {
+	return store.addNotification(ctx, guildID, input.scheduledNotification())
+}
+
+// addNotification inserts the notification under a new random ID, drawing
+// another one when the ID is already taken.
+func (store *SQLiteNotificationConfigStore) addNotification(ctx context.Context, guildID string, notification ScheduledNotification) (string, error) {
+	var id string
+	err := store.inTransaction(ctx, func(tx *sql.Tx) error {
+		config, err := queryGuildConfig(ctx, tx, guildID)
+		if err != nil {
+			return err
+		}
+
+		for attempt := 1; ; attempt++ {
+			if id, err = store.newID(); err != nil {
+				return err
+			}
+
+			created, err := newScheduledNotification(notification, id, guildID, config, store.clock.Now().UTC())
+			if err != nil {
+				return err
+			}
+
+			err = insertNotificationRow(ctx, tx, created)
+			if !isSQLiteDuplicateKey(err) || attempt == maxNotificationIDAttempts {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (store *SQLiteNotificationConfigStore) GetGuildConfig(ctx context.Context, guildID string) (NotificationConfig, error) {
+	return queryGuildConfig(ctx, store.db, guildID)
+}
+
+func (store *SQLiteNotificationConfigStore) ListGuildNotifications(ctx context.Context, guildID string) ([]ScheduledNotification, error) {
+	return queryNotifications(ctx, store.db, "WHERE guild_id = ?", guildID)
+}
+
+func (store *SQLiteNotificationConfigStore) ListNotifications(ctx context.Context) ([]ScheduledNotification, error) {
+	return queryNotifications(ctx, store.db, "")
+}
+
+func (store *SQLiteNotificationConfigStore) UpdateNotification(ctx context.Context, guildID, notificationID string, update NotificationUpdate) (ScheduledNotification, error) {
+	return store.changeNotification(ctx, guildID, notificationID, func(notification *ScheduledNotification, now time.Time) error {
+		updated, err := updateScheduledNotification(*notification, update, now)
+		if err != nil {
+			return err
+		}
+
+		*notification = updated
+		return nil
+	})
+}
+
+func (store *SQLiteNotificationConfigStore) PauseNotification(ctx context.Context, guildID, notificationID string, resumeAt time.Time) (ScheduledNotification, error) {
+	return store.changeNotification(ctx, guildID, notificationID, func(notification *ScheduledNotification, now time.Time) error {
+		err := pauseNotification(notification, resumeAt, now)
+		if errors.Is(err, errNoMoreOccurrences) {
+			return errors.New("la notificación no tiene ocurrencias después de la reanudación")
+		}
+
+		return err
+	})
+}
+
+func (store *SQLiteNotificationConfigStore) ResumeNotification(ctx context.Context, guildID, notificationID string) (ScheduledNotification, error) {
+	return store.changeNotification(ctx, guildID, notificationID, resumeNotification)
+}
+
+// changeNotification applies change to the guild notification and saves it
+// only when change succeeds. When change leaves it without occurrences, the
+// notification is retired and ErrNotificationExpired is returned.
+
+func (store *SQLiteNotificationConfigStore) changeNotification(ctx context.Context, guildID, notificationID string, change func(notification *ScheduledNotification, now time.Time) error) (ScheduledNotification, error) {
+	var changed ScheduledNotification
+	expired := false
+	err := store.inTransaction(ctx, func(tx *sql.Tx) error {
+		notifications, err := queryNotifications(ctx, tx, "WHERE id = ? AND guild_id = ?", notificationID, guildID)
+		if err != nil {
+			return err
+		}
+
+		if len(notifications) == 0 {
+			return errors.New("notificación no encontrada")
+		}
+
+		changed = notifications[0]
+		err = change(&changed, store.clock.Now().UTC())
+		if errors.Is(err, errNoMoreOccurrences) {
+			if err := deleteNotificationRow(ctx, tx, notificationID); err != nil {
+				return err
+			}
+
+			expired = true
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return updateNotificationRow(ctx, tx, changed)
+	})
+	if err != nil {
+		return ScheduledNotification{}, err
+	}
+
+	if expired {
+		return ScheduledNotification{}, ErrNotificationExpired
+	}
+
+	return changed, nil
+}
+
+func (store *SQLiteNotificationConfigStore) PauseGuildNotifications(ctx context.Context, guildID string, resumeAt time.Time) (int, int, error) {
+	skipped := 0
+	paused, err := store.changeGuildNotifications(ctx, guildID, pauseGuildNotification(resumeAt, &skipped))
+	return paused, skipped, err
+}
+
+func (store *SQLiteNotificationConfigStore) ResumeGuildNotifications(ctx context.Context, guildID string) (int, error) {
+	return store.changeGuildNotifications(ctx, guildID, func(notification *ScheduledNotification, now time.Time) (bool, error) {
+		if !notification.IsPaused(now) {
+			return false, nil
+		}
+
+		return true, resumeNotification(notification, now)
+	})
+}
+
+// changeGuildNotifications applies change to every notification of the guild
+// and returns how many it changed, retiring those left without occurrences.
+func (store *SQLiteNotificationConfigStore) changeGuildNotifications(ctx context.Context, guildID string, change func(notification *ScheduledNotification, now time.Time) (bool, error)) (int, error) {
+	changed := 0
+	err := store.inTransaction(ctx, func(tx *sql.Tx) error {
+		notifications, err := queryNotifications(ctx, tx, "WHERE guild_id = ?", guildID)
+		if err != nil {
+			return err
+		}
+
+		now := store.clock.Now().UTC()
+		for _, notification := range notifications {
+			ok, err := change(&notification, now)
+			if errors.Is(err, errNoMoreOccurrences) {
+				if err := deleteNotificationRow(ctx, tx, notification.ID); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				continue
+			}
+
+			if err := updateNotificationRow(ctx, tx, notification); err != nil {
+				return err
+			}
+
+			changed++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
+
+func (store *SQLiteNotificationConfigStore) DeleteNotification(ctx context.Context, guildID, notificationID string) error {
+	result, err := store.db.ExecContext(ctx, "DELETE FROM notifications WHERE id = ? AND guild_id = ?", notificationID, guildID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return errors.New("notificación no encontrada")
+	}
+
+	return nil
+}
+
+func (store *SQLiteNotificationConfigStore) ListDueNotifications(ctx context.Context, now time.Time) ([]ScheduledNotification, error) {
+	notifications, err := queryNotifications(ctx, store.db, "WHERE next_notification_at <= ?", now.UTC().UnixNano())
+	if err != nil {
+		return nil, err
+	}
+
+	dueNotifications := make([]ScheduledNotification, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.hasOccurrencesLeft() && !notification.IsPaused(now) {
+			dueNotifications = append(dueNotifications, notification)
+		}
+	}
+
+	return dueNotifications, nil
+}
+
+func (store *SQLiteNotificationConfigStore) MarkNotificationSent(ctx context.Context, notificationID string, sentAt time.Time) error {
+	return store.inTransaction(ctx, func(tx *sql.Tx) error {
+		notifications, err := queryNotifications(ctx, tx, "WHERE id = ?", notificationID)
+		if err != nil {
+			return err
+		}
+
+		if len(notifications) == 0 {
+			return errors.New("notificación no encontrada")
+		}
+
+		notification := notifications[0]
+		err = advanceAfterDelivery(&notification, sentAt.UTC())
+		if errors.Is(err, errNoMoreOccurrences) {
+			return deleteNotificationRow(ctx, tx, notification.ID)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return updateNotificationRow(ctx, tx, notification)
+	})
+}
+
+func (store *SQLiteNotificationConfigStore) RecalculateAllNextNotifications(ctx context.Context, now time.Time) error {
+	return store.inTransaction(ctx, func(tx *sql.Tx) error {
+		notifications, err := queryNotifications(ctx, tx, "")
+		if err != nil {
+			return err
+		}
+
+		configs := make(map[string]NotificationConfig)
+		normalizedNow := now.UTC()
+		for _, notification := range notifications {
+			config, ok := configs[notification.GuildID]
+			if !ok {
+				if config, err = queryGuildConfig(ctx, tx, notification.GuildID); err != nil {
+					return err
+				}
+
+				configs[notification.GuildID] = config
+			}
+
+			if err := startCatchUp(&notification, config, normalizedNow); err != nil {
+				return err
+			}
+
+			if !notification.IsCatchingUp() {
+				err := rescheduleNotification(&notification, normalizedNow)
+				if errors.Is(err, errNoMoreOccurrences) {
+					if err := deleteNotificationRow(ctx, tx, notification.ID); err != nil {
+						return err
+					}
+
+					continue
+				}
+
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := updateNotificationRow(ctx, tx, notification); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (store *SQLiteNotificationConfigStore) setGuildColumn(ctx context.Context, guildID, column string, value any) error {
+	return upsertGuildColumn(ctx, store.db, guildID, column, value)
+}
+
+func (store *SQLiteNotificationConfigStore) inTransaction(ctx context.Context, run func(tx *sql.Tx) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := run(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// upsertGuildColumn sets a single guild config column; column is always one
+// of the fixed names used in this file, never user input.
+func upsertGuildColumn(ctx context.Context, querier sqlQuerier, guildID, column string, value any) error {
+	query := fmt.Sprintf("INSERT INTO guild_configs (guild_id, %[1]s) VALUES (?, ?) ON CONFLICT (guild_id) DO UPDATE SET %[1]s = excluded.%[1]s", column)
+	_, err := querier.ExecContext(ctx, query, guildID, value)
+	return err
+}
+
+func queryGuildConfig(ctx context.Context, querier sqlQuerier, guildID string) (NotificationConfig, error) {
+	var config NotificationConfig
+	err := querier.QueryRowContext(ctx, "SELECT channel_id, role_id, timezone, catch_up_policy, plain_text, mentions, manager_role_id FROM guild_configs WHERE guild_id = ?", guildID).
+		Scan(&config.ChannelID, &config.RoleID, &config.Timezone, &config.CatchUpPolicy, &config.PlainText, &config.Mentions, &config.ManagerRoleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotificationConfig{}, nil
+	}
+
+	return config, err
+}
+
+func queryNotifications(ctx context.Context, querier sqlQuerier, where string, args ...any) ([]ScheduledNotification, error) {
+	rows, err := querier.QueryContext(ctx, "SELECT "+sqliteNotificationColumns+" FROM notifications "+where+" ORDER BY rowid", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]ScheduledNotification, 0)
+	for rows.Next() {
+		var notification ScheduledNotification
+		var weekdays string
+		var startsAt, endsAt, resumeAt, lastSentAt, catchUpUntil sql.NullInt64
+		var nextNotificationAt int64
+
+		if err := rows.Scan(
+			&notification.ID, &notification.GuildID, &notification.Type, &notification.EveryMinutes,
+			&notification.BaseHour, &notification.Timezone, &weekdays, &notification.MonthDay,
+			&notification.MonthWeek, &notification.CronExpression, &notification.RecurrenceRule,
+			&notification.Date, &notification.Title, &notification.Message, &startsAt, &endsAt,
+			&notification.MaxOccurrences, &notification.CatchUpPolicy, &notification.Color,
+			&notification.ImageURL, &notification.ThumbnailURL, &notification.ChannelID,
+			&notification.RoleID, &notification.Mentions, &notification.Paused, &resumeAt,
+			&notification.OccurrencesSent, &lastSentAt, &catchUpUntil, &nextNotificationAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if notification.Weekdays, err = decodeSQLiteWeekdays(weekdays); err != nil {
+			return nil, err
+		}
+
+		notification.StartsAt = fromSQLiteTime(startsAt)
+		notification.EndsAt = fromSQLiteTime(endsAt)
+		notification.ResumeAt = fromSQLiteTime(resumeAt)
+		notification.LastSentAt = fromSQLiteTime(lastSentAt)
+		notification.CatchUpUntil = fromSQLiteTime(catchUpUntil)
+		notification.NextNotificationAt = time.Unix(0, nextNotificationAt).UTC()
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// insertNotificationRow adds a new notification, failing with a constraint
+// error when its ID is already taken.
+func insertNotificationRow(ctx context.Context, querier sqlQuerier, notification ScheduledNotification) error {
+	_, err := querier.ExecContext(ctx, `INSERT INTO notifications (`+sqliteNotificationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		notificationRowValues(notification)...,
+	)
+	return err
+}
+
+// updateNotificationRow saves an existing notification in place, keeping its
+// rowid and therefore its position in listings. Only a row of the same guild
+// is updated.
+func updateNotificationRow(ctx context.Context, querier sqlQuerier, notification ScheduledNotification) error {
+	values := notificationRowValues(notification)
+	result, err := querier.ExecContext(ctx, `UPDATE notifications SET
+			type = ?, every_minutes = ?, base_hour = ?, timezone = ?, weekdays = ?,
+			month_day = ?, month_week = ?, cron_expression = ?, recurrence_rule = ?,
+			date = ?, title = ?, message = ?, starts_at = ?, ends_at = ?,
+			max_occurrences = ?, catch_up_policy = ?, color = ?, image_url = ?, thumbnail_url = ?,
+			channel_id = ?, role_id = ?, mentions = ?, paused = ?, resume_at = ?,
+			occurrences_sent = ?, last_sent_at = ?, catch_up_until = ?, next_notification_at = ?
+		WHERE id = ? AND guild_id = ?`,
+		append(values[2:], notification.ID, notification.GuildID)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return errors.New("notificación no encontrada")
+	}
+
+	return nil
+}
+
+// notificationRowValues returns the notification fields in the order of
+// sqliteNotificationColumns.
+func notificationRowValues(notification ScheduledNotification) []any {
+	return []any{
+		notification.ID, notification.GuildID, notification.Type, notification.EveryMinutes,
+		notification.BaseHour, notification.Timezone, encodeSQLiteWeekdays(notification.Weekdays),
+		notification.MonthDay, notification.MonthWeek, notification.CronExpression,
+		notification.RecurrenceRule, notification.Date, notification.Title, notification.Message,
+		toSQLiteTime(notification.StartsAt), toSQLiteTime(notification.EndsAt),
+		notification.MaxOccurrences, notification.CatchUpPolicy, notification.Color,
+		notification.ImageURL, notification.ThumbnailURL, notification.ChannelID,
+		notification.RoleID, notification.Mentions, notification.Paused,
+		toSQLiteTime(notification.ResumeAt), notification.OccurrencesSent, toSQLiteTime(notification.LastSentAt), toSQLiteTime(notification.CatchUpUntil),
+		notification.NextNotificationAt.UTC().UnixNano(),
+	}
+}
+
+// isSQLiteDuplicateKey reports whether err is a primary key violation.
+func isSQLiteDuplicateKey(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+func deleteNotificationRow(ctx context.Context, querier sqlQuerier, notificationID string) error {
+	_, err := querier.ExecContext(ctx, "DELETE FROM notifications WHERE id = ?", notificationID)
+	return err
+}
+
+func toSQLiteTime(value time.Time) sql.NullInt64 {
+	if value.IsZero() {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: value.UTC().UnixNano(), Valid: true}
+}
+
+func fromSQLiteTime(value sql.NullInt64) time.Time {
+	if !value.Valid {
+		return time.Time{}
+	}
+
+	return time.Unix(0, value.Int64).UTC()
+}
+
+func encodeSQLiteWeekdays(weekdays []time.Weekday) string {
+	parts := make([]string, 0, len(weekdays))
+	for _, weekday := range weekdays {
+		parts = append(parts, strconv.Itoa(int(weekday)))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func decodeSQLiteWeekdays(value string) ([]time.Weekday, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, ",")
+	weekdays := make([]time.Weekday, 0, len(parts))
+	for _, part := range parts {
+		weekday, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weekday %q in sqlite row: %w", part, err)
+		}
+
+		weekdays = append(weekdays, time.Weekday(weekday))
+	}
+
+	return weekdays, nil
+}
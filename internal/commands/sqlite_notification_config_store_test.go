@@ -0,0 +1,340 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/clock"
+)
+
+func newTestSQLiteStore(t *testing.T, filePath string, clk clock.Clock) *SQLiteNotificationConfigStore {
+	t.Helper()
+
+	store, err := NewSQLiteNotificationConfigStore(filePath, clk)
+	if err != nil {
+		t.Fatalf("expected nil error opening sqlite store, got %v", err)
+	}
+
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSQLiteNotificationConfigStorePersistsConfigAndNotifications(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notifications.db")
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := newTestSQLiteStore(t, filePath, fakeClock)
+	ctx := context.Background()
+
+	if err := store.SetChannel(ctx, "guild-1", "channel-1"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := store.SetRole(ctx, "guild-1", "role-1"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := store.SetTimezone(ctx, "guild-1", "America/Caracas"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := store.SetPlainText(ctx, "guild-1", true); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := store.SetMentions(ctx, "guild-1", "<@&5> @here"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := store.SetManagerRole(ctx, "guild-1", "managers"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	weeklyID, err := store.AddWeeklyNotification(ctx, "guild-1", WeeklyNotificationInput{
+		BaseHour:           "09:00",
+		Weekdays:           []time.Weekday{time.Monday, time.Friday},
+		Title:              "Semanal",
+		Message:            "Reunión",
+		NotificationLimits: NotificationLimits{EndsAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), MaxOccurrences: 10},
+		NotificationStyle:  NotificationStyle{Color: 0x5865F2, ImageURL: "https://example.com/image.png", ThumbnailURL: "https://example.com/thumb.png"},
+		NotificationTarget: NotificationTarget{ChannelID: "channel-2", RoleID: "role-2", Mentions: "<@7>"},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error creating weekly notification, got %v", err)
+	}
+
+	if _, err := store.AddDailyNotification(ctx, "guild-2", DailyNotificationInput{BaseHour: "10:00", Title: "Otro", Message: "Otro servidor"}); err != nil {
+		t.Fatalf("expected nil error creating daily notification, got %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("expected nil error closing store, got %v", err)
+	}
+
+	reopened := newTestSQLiteStore(t, filePath, fakeClock)
+	config, err := reopened.GetGuildConfig(ctx, "guild-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if config.ChannelID != "channel-1" || config.RoleID != "role-1" || config.Timezone != "America/Caracas" || !config.PlainText || config.Mentions != "<@&5> @here" || config.ManagerRoleID != "managers" {
+		t.Fatalf("unexpected guild config: %+v", config)
+	}
+
+	notifications, err := reopened.ListGuildNotifications(ctx, "guild-1")
+	if err != nil || len(notifications) != 1 || notifications[0].ID != weeklyID {
+		t.Fatalf("expected only the weekly guild notification, got %+v (%v)", notifications, err)
+	}
+
+	notification := notifications[0]
+	if notification.Timezone != "America/Caracas" || len(notification.Weekdays) != 2 || notification.Weekdays[1] != time.Friday || notification.MaxOccurrences != 10 {
+		t.Fatalf("unexpected stored notification: %+v", notification)
+	}
+
+	if expected := (NotificationStyle{Color: 0x5865F2, ImageURL: "https://example.com/image.png", ThumbnailURL: "https://example.com/thumb.png"}); notification.NotificationStyle != expected {
+		t.Fatalf("expected style %+v, got %+v", expected, notification.NotificationStyle)
+	}
+
+	if expected := (NotificationTarget{ChannelID: "channel-2", RoleID: "role-2", Mentions: "<@7>"}); notification.NotificationTarget != expected {
+		t.Fatalf("expected target %+v, got %+v", expected, notification.NotificationTarget)
+	}
+
+	// Friday 2024-03-01 09:00 in Caracas is 13:00 UTC, an hour after the clock.
+	if expected := time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC); !notification.NextNotificationAt.Equal(expected) {
+		t.Fatalf("expected next notification at %v, got %v", expected, notification.NextNotificationAt)
+	}
+
+	all, err := reopened.ListNotifications(ctx)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("expected two notifications overall, got %+v (%v)", all, err)
+	}
+}
+
+func TestSQLiteNotificationConfigStoreDeliversAndRetires(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := newTestSQLiteStore(t, filepath.Join(t.TempDir(), "notifications.db"), fakeClock)
+	ctx := context.Background()
+
+	id, err := store.AddByMinutesNotification(ctx, "guild-1", ByMinutesNotificationInput{
+		EveryMinutes:       30,
+		BaseHour:           "00:00",
+		Title:              "Agua",
+		Message:            "Toma agua",
+		NotificationLimits: NotificationLimits{MaxOccurrences: 2},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	due, err := store.ListDueNotifications(ctx, fakeClock.Now())
+	if err != nil || len(due) != 0 {
+		t.Fatalf("expected nothing due yet, got %+v (%v)", due, err)
+	}
+
+	for delivery := 1; delivery <= 2; delivery++ {
+		fakeClock.Advance(30 * time.Minute)
+
+		due, err := store.ListDueNotifications(ctx, fakeClock.Now())
+		if err != nil || len(due) != 1 || due[0].ID != id {
+			t.Fatalf("expected notification due for delivery %d, got %+v (%v)", delivery, due, err)
+		}
+
+		if err := store.MarkNotificationSent(ctx, id, fakeClock.Now()); err != nil {
+			t.Fatalf("expected nil error marking delivery %d, got %v", delivery, err)
+		}
+	}
+
+	notifications, err := store.ListNotifications(ctx)
+	if err != nil || len(notifications) != 0 {
+		t.Fatalf("expected notification to be retired after max occurrences, got %+v (%v)", notifications, err)
+	}
+
+	if err := store.MarkNotificationSent(ctx, id, fakeClock.Now()); err == nil {
+		t.Fatal("expected error marking a retired notification, got nil")
+	}
+
+	if err := store.DeleteNotification(ctx, "guild-1", id); err == nil {
+		t.Fatal("expected error deleting a retired notification, got nil")
+	}
+}
+
+func TestSQLiteNotificationConfigStoreRetriesTakenIDs(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t, filepath.Join(t.TempDir(), "alicia.db"), clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	ids := []string{"aaaaaa", "aaaaaa", "bbbbbb"}
+	store.newID = func() (string, error) {
+		id := ids[0]
+		ids = ids[1:]
+		return id, nil
+	}
+
+	if _, err := store.AddDailyNotification(ctx, "guild-2", DailyNotificationInput{BaseHour: "10:00", Title: "Otro", Message: "Otro servidor"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	id, err := store.AddDailyNotification(ctx, "guild-1", DailyNotificationInput{BaseHour: "09:00", Title: "Diario", Message: "Hola"})
+	if err != nil || id != "bbbbbb" {
+		t.Fatalf("expected a new id after the collision, got %q (%v)", id, err)
+	}
+
+	other, err := store.ListGuildNotifications(ctx, "guild-2")
+	if err != nil || len(other) != 1 || other[0].ID != "aaaaaa" || other[0].Title != "Otro" {
+		t.Fatalf("expected the existing notification to be kept, got %+v (%v)", other, err)
+	}
+
+	title := "Robado"
+	if _, err := store.UpdateNotification(ctx, "guild-1", "aaaaaa", NotificationUpdate{Title: &title}); err == nil {
+		t.Fatal("expected an error updating a notification of another guild, got nil")
+	}
+}
+
+func TestSQLiteNotificationConfigStorePauseAllKeepsFinishedNotifications(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := newTestSQLiteStore(t, filepath.Join(t.TempDir(), "alicia.db"), fakeClock)
+	testPauseAllKeepsFinishedNotifications(t, store, fakeClock)
+}
+
+func TestSQLiteNotificationConfigStoreResumeRetiresExpiredReminder(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := newTestSQLiteStore(t, filepath.Join(t.TempDir(), "alicia.db"), fakeClock)
+	testResumeRetiresExpiredReminder(t, store, fakeClock)
+}
+
+func TestSQLiteNotificationConfigStoreUpdatesNotification(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := newTestSQLiteStore(t, filepath.Join(t.TempDir(), "notifications.db"), fakeClock)
+	ctx := context.Background()
+
+	id, err := store.AddWeeklyNotification(ctx, "guild-1", WeeklyNotificationInput{
+		BaseHour: "09:00",
+		Weekdays: []time.Weekday{time.Monday},
+		Title:    "Semanal",
+		Message:  "Reunión",
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	title := "Planificación"
+	if _, err := store.UpdateNotification(ctx, "guild-1", id, NotificationUpdate{Title: &title, Weekdays: []time.Weekday{time.Saturday}}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	notifications, err := store.ListGuildNotifications(ctx, "guild-1")
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("expected one notification, got %+v (%v)", notifications, err)
+	}
+
+	notification := notifications[0]
+	if notification.ID != id || notification.Title != "Planificación" || len(notification.Weekdays) != 1 || notification.Weekdays[0] != time.Saturday {
+		t.Fatalf("unexpected updated notification: %+v", notification)
+	}
+
+	// Saturday 2024-03-02 comes before the original Monday occurrence.
+	if expected := time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC); !notification.NextNotificationAt.Equal(expected) {
+		t.Fatalf("expected next notification at %v, got %v", expected, notification.NextNotificationAt)
+	}
+
+	if _, err := store.UpdateNotification(ctx, "guild-1", "missing", NotificationUpdate{Title: &title}); err == nil {
+		t.Fatal("expected error editing a missing notification, got nil")
+	}
+}
+
+func TestSQLiteNotificationConfigStoreCatchesUpAfterDowntime(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFake(start)
+	store := newTestSQLiteStore(t, filepath.Join(t.TempDir(), "notifications.db"), fakeClock)
+	ctx := context.Background()
+
+	if err := store.SetCatchUpPolicy(ctx, "guild-1", "fire_all_up_to_2"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	id, err := store.AddDailyNotification(ctx, "guild-1", DailyNotificationInput{BaseHour: "09:00", Title: "Diario", Message: "Diario"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	now := start.AddDate(0, 0, 3).Add(10 * time.Hour)
+	if err := store.RecalculateAllNextNotifications(ctx, now); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	due, err := store.ListDueNotifications(ctx, now)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("expected one catch-up notification due, got %+v (%v)", due, err)
+	}
+
+	firstMissed := time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC)
+	if !due[0].NextNotificationAt.Equal(firstMissed) || !due[0].CatchUpUntil.Equal(firstMissed.AddDate(0, 0, 1)) {
+		t.Fatalf("expected the last two missed occurrences to be delivered, got %+v", due[0])
+	}
+
+	for range 2 {
+		if err := store.MarkNotificationSent(ctx, id, now); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	notifications, err := store.ListNotifications(ctx)
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("expected notification to remain, got %+v (%v)", notifications, err)
+	}
+
+	if expected := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC); !notifications[0].NextNotificationAt.Equal(expected) || notifications[0].IsCatchingUp() {
+		t.Fatalf("expected schedule to resume at %v, got %+v", expected, notifications[0])
+	}
+}
+
+func TestSQLiteNotificationConfigStoreMigratesExistingDatabase(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notifications.db")
+	db, err := sql.Open("sqlite", "file:"+filePath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		t.Fatalf("failed to create the original schema: %v", err)
+	}
+
+	nextAt := time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC).UnixNano()
+	if _, err := db.Exec(`INSERT INTO notifications (id, guild_id, type, base_hour, title, message, next_notification_at)
+		VALUES ('n-1', 'guild-1', 'daily', '13:00', 'Diario', 'Hola', ?)`, nextAt); err != nil {
+		t.Fatalf("failed to insert notification: %v", err)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := newTestSQLiteStore(t, filePath, fakeClock)
+	ctx := context.Background()
+
+	resumeAt := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+	if _, err := store.PauseNotification(ctx, "guild-1", "n-1", resumeAt); err != nil {
+		t.Fatalf("expected nil error pausing migrated notification, got %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("expected nil error closing store, got %v", err)
+	}
+
+	reopened := newTestSQLiteStore(t, filePath, fakeClock)
+	notifications, err := reopened.ListGuildNotifications(ctx, "guild-1")
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("expected one notification, got %+v (%v)", notifications, err)
+	}
+
+	notification := notifications[0]
+	if !notification.Paused || !notification.ResumeAt.Equal(resumeAt) || !notification.NextNotificationAt.Equal(time.Date(2024, 3, 3, 13, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected paused notification: %+v", notification)
+	}
+
+	due, err := reopened.ListDueNotifications(ctx, time.Date(2024, 3, 2, 13, 0, 0, 0, time.UTC))
+	if err != nil || len(due) != 0 {
+		t.Fatalf("expected nothing due while paused, got %+v (%v)", due, err)
+	}
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+type timezoneCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewTimezoneCommand(configStore NotificationConfigStore) Command {
+	return &timezoneCommand{configStore: configStore}
+}
+
+func (command *timezoneCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "timezone",
+		Description: "Configura la zona horaria del servidor para las notificaciones",
+		Options: []discord.SlashCommandOption{
+			{
+				Name:        "timezone",
+				Description: "Zona horaria IANA, por ejemplo America/Caracas",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (command *timezoneCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	timezone := strings.TrimSpace(interaction.Options["timezone"])
+	if timezone == "" {
+		return "", MissingRequiredOptionError("timezone")
+	}
+
+	if _, err := loadNotificationLocation(timezone); err != nil {
+		return "", fmt.Errorf("zona horaria inválida: %s; usa un nombre IANA como America/Caracas", timezone)
+	}
+
+	if err := command.configStore.SetTimezone(ctx, interaction.GuildID, timezone); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Zona horaria de notificaciones configurada a %s", timezone), nil
+}
+
+func guildTimezoneName(config NotificationConfig) string {
+	if strings.TrimSpace(config.Timezone) == "" {
+		return "UTC"
+	}
+
+	return config.Timezone
+}
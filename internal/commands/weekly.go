@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/discord"
+)
+
+var spanishWeekdayNames = map[string]time.Weekday{
+	"lun": time.Monday, "lunes": time.Monday,
+	"mar": time.Tuesday, "martes": time.Tuesday,
+	"mie": time.Wednesday, "mié": time.Wednesday, "miercoles": time.Wednesday, "miércoles": time.Wednesday,
+	"jue": time.Thursday, "jueves": time.Thursday,
+	"vie": time.Friday, "viernes": time.Friday,
+	"sab": time.Saturday, "sáb": time.Saturday, "sabado": time.Saturday, "sábado": time.Saturday,
+	"dom": time.Sunday, "domingo": time.Sunday,
+}
+
+var spanishWeekdayShortNames = [...]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"}
+
+type weeklyCommand struct {
+	managerCommand
+	configStore NotificationConfigStore
+}
+
+func NewWeeklyCommand(configStore NotificationConfigStore) Command {
+	return &weeklyCommand{configStore: configStore}
+}
+
+func (command *weeklyCommand) Definition() discord.SlashCommand {
+	return discord.SlashCommand{
+		Name:        "weekly",
+		Description: "Crea una notificación semanal en los días seleccionados",
+		Options: append([]discord.SlashCommandOption{
+			{
+				Name:        "base_hour",
+				Description: "Hora base en la zona horaria del servidor, formato HH:MM (24h)",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "days",
+				Description: "Días separados por comas, por ejemplo lun,mié,vie",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "title",
+				Description: "Título de la notificación",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+			{
+				Name:        "message",
+				Description: "Mensaje de la notificación; admite marcadores como {fecha}, {rol} o {faltan:AAAA-MM-DD}",
+				Type:        discord.SlashCommandOptionTypeString,
+				Required:    true,
+			},
+		}, notificationOptions()...),
+	}
+}
+
+func (command *weeklyCommand) Execute(ctx context.Context, interaction discord.Interaction) (string, error) {
+	if interaction.GuildID == "" {
+		return "", ErrCommandOnlyInGuild
+	}
+
+	baseHour := interaction.Options["base_hour"]
+	if baseHour == "" {
+		return "", MissingRequiredOptionError("base_hour")
+	}
+
+	daysRaw := strings.TrimSpace(interaction.Options["days"])
+	if daysRaw == "" {
+		return "", MissingRequiredOptionError("days")
+	}
+
+	title := strings.TrimSpace(interaction.Options["title"])
+	if title == "" {
+		return "", MissingRequiredOptionError("title")
+	}
+
+	message := strings.TrimSpace(interaction.Options["message"])
+	if message == "" {
+		return "", MissingRequiredOptionError("message")
+	}
+
+	if err := validateNotificationTemplates(title, message); err != nil {
+		return "", err
+	}
+
+	if _, _, err := parseBaseHour(baseHour); err != nil {
+		return "", err
+	}
+
+	weekdays, err := parseSpanishWeekdays(daysRaw)
+	if err != nil {
+		return "", err
+	}
+
+	guildConfig, err := command.configStore.GetGuildConfig(ctx, interaction.GuildID)
+	if err != nil {
+		return "", err
+	}
+
+	limits, err := parseNotificationLimits(interaction.Options, guildConfig.Timezone)
+	if err != nil {
+		return "", err
+	}
+
+	style, err := parseNotificationStyle(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := parseNotificationTarget(interaction.Options)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := command.configStore.AddWeeklyNotification(ctx, interaction.GuildID, WeeklyNotificationInput{
+		BaseHour:           baseHour,
+		Weekdays:           weekdays,
+		Title:              title,
+		Message:            message,
+		NotificationLimits: limits,
+		NotificationStyle:  style,
+		NotificationTarget: target,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Notificación creada correctamente (%s, hora base en %s). ID: %s", formatSpanishWeekdays(weekdays), guildTimezoneName(guildConfig), id), nil
+}
+
+// parseSpanishWeekdays accepts Spanish day names or abbreviations separated by
+// commas or spaces and returns them without duplicates, Monday first.
+func parseSpanishWeekdays(value string) ([]time.Weekday, error) {
+	parts := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';'
+	})
+
+	seen := make(map[time.Weekday]bool)
+	weekdays := make([]time.Weekday, 0, len(parts))
+	for _, part := range parts {
+		weekday, ok := spanishWeekdayNames[part]
+		if !ok {
+			return nil, fmt.Errorf("día inválido: %s; usa lun, mar, mié, jue, vie, sáb o dom", part)
+		}
+
+		if seen[weekday] {
+			continue
+		}
+
+		seen[weekday] = true
+		weekdays = append(weekdays, weekday)
+	}
+
+	if len(weekdays) == 0 {
+		return nil, MissingRequiredOptionError("days")
+	}
+
+	sort.Slice(weekdays, func(i, j int) bool {
+		return (weekdays[i]+6)%7 < (weekdays[j]+6)%7
+	})
+
+	return weekdays, nil
+}
+
+func formatSpanishWeekdays(weekdays []time.Weekday) string {
+	names := make([]string, 0, len(weekdays))
+	for _, weekday := range weekdays {
+		names = append(names, spanishWeekdayShortNames[weekday])
+	}
+
+	return strings.Join(names, ", ")
+}
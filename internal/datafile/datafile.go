@@ -0,0 +1,114 @@
+// Package datafile writes the application's JSON state files safely and
+// upgrades them between schema versions.
+package datafile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+)
+
+// WriteAtomic replaces path with content so that readers and crashes only
+// ever observe the old or the new file: the data is written and synced to a
+// temporary file in the same directory, renamed over path, and the directory
+// is synced so the rename itself survives a power loss.
+func WriteAtomic(path string, content []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tempPath := file.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tempPath)
+		}
+	}()
+
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Chmod(perm); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		return err
+	}
+
+	committed = true
+	return syncDirectory(dir)
+}
+
+// RemoveDurably deletes path, if it exists, and syncs its directory.
+func RemoveDurably(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return syncDirectory(filepath.Dir(path))
+}
+
+// RemoveTemporaryFiles deletes the temporary files that interrupted
+// WriteAtomic calls left next to each of paths.
+func RemoveTemporaryFiles(paths ...string) error {
+	for _, path := range paths {
+		matches, err := filepath.Glob(path + ".tmp-*")
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			if err := os.Remove(match); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func syncDirectory(dir string) error {
+	// Directories cannot be opened for syncing on Windows.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	directory, err := os.Open(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+	defer directory.Close()
+
+	// Some filesystems do not support syncing directories; the rename is
+	// still atomic there, just not guaranteed durable.
+	if err := directory.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+		return err
+	}
+
+	return nil
+}
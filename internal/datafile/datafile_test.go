@@ -0,0 +1,27 @@
+package datafile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAtomicReplacesContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	for _, content := range []string{"first", "second"} {
+		if err := WriteAtomic(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		written, err := os.ReadFile(path)
+		if err != nil || string(written) != content {
+			t.Fatalf("expected %q, got %q (%v)", content, written, err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".tmp-*")
+	if err != nil || len(matches) != 0 {
+		t.Fatalf("expected no temporary files, got %v (%v)", matches, err)
+	}
+}
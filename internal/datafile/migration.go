@@ -0,0 +1,142 @@
+package datafile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Step upgrades the content of a state file by exactly one schema version.
+// The returned document must carry the new schema_version.
+type Step func(content []byte) ([]byte, error)
+
+// Registry lists the steps that bring a state file to its current schema
+// version, keyed by the version each step upgrades from. Files written
+// before versioning have no schema_version and count as version 0.
+type Registry struct {
+	Current int
+	Steps   map[int]Step
+}
+
+// Migrate upgrades path to the registry's current version, one step at a
+// time, after copying the original file to BackupPath. It reports whether
+// the file was upgraded; a missing file is left alone.
+func Migrate(path string, registry Registry) (bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	version, err := Version(content)
+	if err != nil {
+		return false, fmt.Errorf("read schema version of %s: %w", path, err)
+	}
+
+	if version == registry.Current {
+		return false, nil
+	}
+
+	if version > registry.Current {
+		return false, fmt.Errorf("%s has schema version %d, newer than the supported %d", path, version, registry.Current)
+	}
+
+	upgraded := content
+	for from := version; from < registry.Current; from++ {
+		step, ok := registry.Steps[from]
+		if !ok {
+			return false, fmt.Errorf("no migration for %s from schema version %d", path, from)
+		}
+
+		upgraded, err = step(upgraded)
+		if err != nil {
+			return false, fmt.Errorf("migrate %s from schema version %d: %w", path, from, err)
+		}
+
+		if got, err := Version(upgraded); err != nil || got != from+1 {
+			return false, fmt.Errorf("migration of %s from schema version %d did not produce version %d", path, from, from+1)
+		}
+	}
+
+	if err := copyToBackup(path, version, content); err != nil {
+		return false, fmt.Errorf("back up %s: %w", path, err)
+	}
+
+	if err := WriteAtomic(path, upgraded, 0o644); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Version returns the schema_version of a JSON document, or 0 when it has
+// none.
+func Version(content []byte) (int, error) {
+	var document struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+
+	if err := json.Unmarshal(content, &document); err != nil {
+		return 0, err
+	}
+
+	return document.SchemaVersion, nil
+}
+
+// WithVersion returns the JSON object in content with its schema_version set
+// to version, for steps that only need to stamp the new version.
+func WithVersion(content []byte, version int) ([]byte, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	if document == nil {
+		document = make(map[string]json.RawMessage)
+	}
+
+	document["schema_version"] = json.RawMessage(fmt.Sprint(version))
+	return json.MarshalIndent(document, "", "  ")
+}
+
+// BackupPath is where the copy of path taken before migrating it from
+// version is kept.
+func BackupPath(path string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", path, version)
+}
+
+// MoveToBackup renames a file that a migration made obsolete to its backup
+// path. When a backup from an earlier attempt exists it is kept and path is
+// removed instead.
+func MoveToBackup(path string, version int) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	backupPath := BackupPath(path, version)
+	if _, err := os.Stat(backupPath); err == nil {
+		return RemoveDurably(path)
+	}
+
+	if err := os.Rename(path, backupPath); err != nil {
+		return err
+	}
+
+	return syncDirectory(filepath.Dir(path))
+}
+
+// copyToBackup keeps the first backup taken of a version, so retrying an
+// interrupted migration never overwrites the original file.
+func copyToBackup(path string, version int, content []byte) error {
+	backupPath := BackupPath(path, version)
+	if _, err := os.Stat(backupPath); err == nil {
+		return nil
+	}
+
+	return WriteAtomic(backupPath, content, 0o644)
+}
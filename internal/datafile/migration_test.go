@@ -0,0 +1,98 @@
+package datafile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateUpgradesStepByStepAndKeepsBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	original := `{"name":"alicia"}`
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatalf("failed to prepare file: %v", err)
+	}
+
+	applied := make([]int, 0)
+	registry := Registry{
+		Current: 2,
+		Steps: map[int]Step{
+			0: func(content []byte) ([]byte, error) {
+				applied = append(applied, 0)
+				return WithVersion(content, 1)
+			},
+			1: func(content []byte) ([]byte, error) {
+				applied = append(applied, 1)
+				var document map[string]any
+				if err := json.Unmarshal(content, &document); err != nil {
+					return nil, err
+				}
+
+				document["schema_version"] = 2
+				document["name"] = strings.ToUpper(document["name"].(string))
+				return json.Marshal(document)
+			},
+		},
+	}
+
+	migrated, err := Migrate(path, registry)
+	if err != nil || !migrated {
+		t.Fatalf("expected migration, got %v (%v)", migrated, err)
+	}
+
+	if len(applied) != 2 || applied[0] != 0 || applied[1] != 1 {
+		t.Fatalf("expected steps 0 and 1 in order, got %v", applied)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if version, err := Version(content); err != nil || version != 2 || !strings.Contains(string(content), "ALICIA") {
+		t.Fatalf("unexpected migrated content %s (%v)", content, err)
+	}
+
+	backup, err := os.ReadFile(BackupPath(path, 0))
+	if err != nil || string(backup) != original {
+		t.Fatalf("expected backup of the original file, got %q (%v)", backup, err)
+	}
+
+	migrated, err = Migrate(path, registry)
+	if err != nil || migrated || len(applied) != 2 {
+		t.Fatalf("expected current file to be left alone, got %v (%v)", migrated, err)
+	}
+}
+
+func TestMigrateRejectsUnknownVersions(t *testing.T) {
+	dir := t.TempDir()
+	registry := Registry{Current: 2, Steps: map[int]Step{1: func(content []byte) ([]byte, error) { return WithVersion(content, 2) }}}
+
+	newer := filepath.Join(dir, "newer.json")
+	if err := os.WriteFile(newer, []byte(`{"schema_version":3}`), 0o644); err != nil {
+		t.Fatalf("failed to prepare file: %v", err)
+	}
+
+	if _, err := Migrate(newer, registry); err == nil {
+		t.Fatal("expected error for a newer schema version, got nil")
+	}
+
+	missingStep := filepath.Join(dir, "missing.json")
+	if err := os.WriteFile(missingStep, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("failed to prepare file: %v", err)
+	}
+
+	if _, err := Migrate(missingStep, registry); err == nil {
+		t.Fatal("expected error for a version without a step, got nil")
+	}
+
+	if _, err := os.Stat(BackupPath(missingStep, 0)); !os.IsNotExist(err) {
+		t.Fatalf("expected no backup when the migration fails, got %v", err)
+	}
+
+	if migrated, err := Migrate(filepath.Join(dir, "absent.json"), registry); err != nil || migrated {
+		t.Fatalf("expected missing file to be left alone, got %v (%v)", migrated, err)
+	}
+}
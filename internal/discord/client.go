@@ -3,6 +3,7 @@ package discord
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/bwmarrin/discordgo"
@@ -13,10 +14,15 @@ type discordSession interface {
 	Close() error
 	AddMessageCreateHandler(handler func(message *discordgo.MessageCreate))
 	AddInteractionCreateHandler(handler func(interaction *discordgo.InteractionCreate))
-	ApplicationCommandCreate(command SlashCommand) (string, error)
-	ApplicationCommands() ([]RegisteredSlashCommand, error)
+	ApplicationCommandCreate(guildID string, command SlashCommand) (string, error)
+	ApplicationCommands(guildID string) ([]RegisteredSlashCommand, error)
+	ApplicationCommandEdit(guildID, commandID string, command SlashCommand) error
+	ApplicationCommandDelete(guildID, commandID string) error
+	ApplicationCommandBulkOverwrite(guildID string, commands []SlashCommand) ([]RegisteredSlashCommand, error)
 	InteractionRespond(interaction *discordgo.Interaction, content string) error
-	ChannelMessageSend(channelID, content string) error
+	InteractionRespondAutocomplete(interaction *discordgo.Interaction, choices []AutocompleteChoice) error
+	ChannelMessageSend(channelID, content string, mentions AllowedMentions) error
+	ChannelMessageSendEmbed(channelID, content string, embed Embed, mentions AllowedMentions) error
 }
 
 type discordGoSession struct {
@@ -43,30 +49,15 @@ func (discordSession *discordGoSession) AddInteractionCreateHandler(handler func
 	})
 }
 
-func (discordSession *discordGoSession) ApplicationCommandCreate(command SlashCommand) (string, error) {
-	if discordSession.session.State == nil || discordSession.session.State.User == nil {
-		return "", errors.New("discord session user state is not initialized")
-	}
-
-	options := make([]*discordgo.ApplicationCommandOption, 0, len(command.Options))
-	for _, option := range command.Options {
-		options = append(options, &discordgo.ApplicationCommandOption{
-			Type:        toDiscordOptionType(option.Type),
-			Name:        option.Name,
-			Description: option.Description,
-			Required:    option.Required,
-		})
+// The application command methods act on global commands when guildID is
+// empty and on the commands of that guild otherwise.
+func (discordSession *discordGoSession) ApplicationCommandCreate(guildID string, command SlashCommand) (string, error) {
+	applicationID, err := discordSession.applicationID()
+	if err != nil {
+		return "", err
 	}
 
-	createdCommand, err := discordSession.session.ApplicationCommandCreate(
-		discordSession.session.State.User.ID,
-		"",
-		&discordgo.ApplicationCommand{
-			Name:        command.Name,
-			Description: command.Description,
-			Options:     options,
-		},
-	)
+	createdCommand, err := discordSession.session.ApplicationCommandCreate(applicationID, guildID, toDiscordApplicationCommand(command))
 	if err != nil {
 		return "", err
 	}
@@ -74,36 +65,120 @@ func (discordSession *discordGoSession) ApplicationCommandCreate(command SlashCo
 	return createdCommand.ID, nil
 }
 
-func (discordSession *discordGoSession) ApplicationCommands() ([]RegisteredSlashCommand, error) {
-	if discordSession.session.State == nil || discordSession.session.State.User == nil {
-		return nil, errors.New("discord session user state is not initialized")
+func (discordSession *discordGoSession) ApplicationCommands(guildID string) ([]RegisteredSlashCommand, error) {
+	applicationID, err := discordSession.applicationID()
+	if err != nil {
+		return nil, err
 	}
 
-	commands, err := discordSession.session.ApplicationCommands(discordSession.session.State.User.ID, "")
+	commands, err := discordSession.session.ApplicationCommands(applicationID, guildID)
 	if err != nil {
 		return nil, err
 	}
 
 	registered := make([]RegisteredSlashCommand, 0, len(commands))
 	for _, command := range commands {
-		registered = append(registered, RegisteredSlashCommand{ID: command.ID, Name: command.Name})
+		registered = append(registered, fromDiscordApplicationCommand(command))
 	}
 
 	return registered, nil
 }
 
+func (discordSession *discordGoSession) ApplicationCommandEdit(guildID, commandID string, command SlashCommand) error {
+	applicationID, err := discordSession.applicationID()
+	if err != nil {
+		return err
+	}
+
+	_, err = discordSession.session.ApplicationCommandEdit(applicationID, guildID, commandID, toDiscordApplicationCommand(command))
+	return err
+}
+
+func (discordSession *discordGoSession) ApplicationCommandDelete(guildID, commandID string) error {
+	applicationID, err := discordSession.applicationID()
+	if err != nil {
+		return err
+	}
+
+	return discordSession.session.ApplicationCommandDelete(applicationID, guildID, commandID)
+}
+
+func (discordSession *discordGoSession) ApplicationCommandBulkOverwrite(guildID string, commands []SlashCommand) ([]RegisteredSlashCommand, error) {
+	applicationID, err := discordSession.applicationID()
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]*discordgo.ApplicationCommand, 0, len(commands))
+	for _, command := range commands {
+		definitions = append(definitions, toDiscordApplicationCommand(command))
+	}
+
+	overwritten, err := discordSession.session.ApplicationCommandBulkOverwrite(applicationID, guildID, definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make([]RegisteredSlashCommand, 0, len(overwritten))
+	for _, command := range overwritten {
+		registered = append(registered, fromDiscordApplicationCommand(command))
+	}
+
+	return registered, nil
+}
+
+func (discordSession *discordGoSession) applicationID() (string, error) {
+	if discordSession.session.State == nil || discordSession.session.State.User == nil {
+		return "", errors.New("discord session user state is not initialized")
+	}
+
+	return discordSession.session.State.User.ID, nil
+}
+
+// InteractionRespond never pings: command replies often echo mentions, such as
+// the role just configured, that should only notify on real notifications.
 func (discordSession *discordGoSession) InteractionRespond(interaction *discordgo.Interaction, content string) error {
 	return discordSession.session.InteractionRespond(
 		interaction,
 		&discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{Content: content},
+			Data: &discordgo.InteractionResponseData{
+				Content:         content,
+				AllowedMentions: toDiscordAllowedMentions(AllowedMentions{}),
+			},
+		},
+	)
+}
+
+func (discordSession *discordGoSession) InteractionRespondAutocomplete(interaction *discordgo.Interaction, choices []AutocompleteChoice) error {
+	options := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(choices))
+	for _, choice := range choices {
+		options = append(options, &discordgo.ApplicationCommandOptionChoice{Name: choice.Name, Value: choice.Value})
+	}
+
+	return discordSession.session.InteractionRespond(
+		interaction,
+		&discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{Choices: options},
 		},
 	)
 }
 
-func (discordSession *discordGoSession) ChannelMessageSend(channelID, content string) error {
-	_, err := discordSession.session.ChannelMessageSend(channelID, content)
+func (discordSession *discordGoSession) ChannelMessageSend(channelID, content string, mentions AllowedMentions) error {
+	_, err := discordSession.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: toDiscordAllowedMentions(mentions),
+	})
+	return err
+}
+
+func (discordSession *discordGoSession) ChannelMessageSendEmbed(channelID, content string, embed Embed, mentions AllowedMentions) error {
+	_, err := discordSession.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         content,
+		Embeds:          []*discordgo.MessageEmbed{toDiscordEmbed(embed)},
+		AllowedMentions: toDiscordAllowedMentions(mentions),
+	})
 	return err
 }
 
@@ -117,15 +192,35 @@ type Message struct {
 
 type MessageCreateHandler func(message Message)
 
+// Permission bits, as used by DefaultMemberPermissions and
+// Interaction.MemberPermissions.
+const (
+	PermissionAdministrator int64 = discordgo.PermissionAdministrator
+	PermissionManageServer  int64 = discordgo.PermissionManageGuild
+)
+
+// SlashCommand describes a command. DefaultMemberPermissions hides the command
+// from members without all of those permissions until a server admin changes
+// who may use it; zero makes it available to everyone.
 type SlashCommand struct {
-	Name        string
-	Description string
-	Options     []SlashCommandOption
+	Name                     string
+	Description              string
+	Options                  []SlashCommandOption
+	DefaultMemberPermissions int64
+}
+
+// Equal reports whether two definitions would be registered identically.
+func (command SlashCommand) Equal(other SlashCommand) bool {
+	return command.Name == other.Name &&
+		command.Description == other.Description &&
+		slices.Equal(command.Options, other.Options) &&
+		command.DefaultMemberPermissions == other.DefaultMemberPermissions
 }
 
+// RegisteredSlashCommand is a command as Discord currently has it registered.
 type RegisteredSlashCommand struct {
-	ID   string
-	Name string
+	ID string
+	SlashCommand
 }
 
 type SlashCommandOptionType string
@@ -137,21 +232,54 @@ const (
 	SlashCommandOptionTypeRole    SlashCommandOptionType = "role"
 )
 
+// SlashCommandOption describes a command option. Autocomplete options get
+// their suggestions from the bot while the user types instead of a fixed list.
 type SlashCommandOption struct {
-	Name        string
-	Description string
-	Type        SlashCommandOptionType
-	Required    bool
+	Name         string
+	Description  string
+	Type         SlashCommandOptionType
+	Required     bool
+	Autocomplete bool
 }
 
+// Interaction is either a command invocation or, when Autocomplete is set, a
+// request for suggestions for FocusedOption, whose partial value is in Options.
 type Interaction struct {
-	ID          string
-	CommandName string
-	ChannelID   string
-	GuildID     string
-	UserID      string
-	Options     map[string]string
-	raw         *discordgo.Interaction
+	ID            string
+	CommandName   string
+	ChannelID     string
+	GuildID       string
+	UserID        string
+	Options       map[string]string
+	Autocomplete  bool
+	FocusedOption string
+	// MemberPermissions and MemberRoleIDs describe the member who sent the
+	// interaction; both are empty outside a guild.
+	MemberPermissions int64
+	MemberRoleIDs     []string
+	raw               *discordgo.Interaction
+}
+
+// MaxAutocompleteChoices is the most suggestions Discord shows for an option.
+const MaxAutocompleteChoices = 25
+
+// maxAutocompleteChoiceNameLength is the longest choice name Discord accepts.
+const maxAutocompleteChoiceNameLength = 100
+
+// AutocompleteChoice is a suggestion shown as Name that fills in Value.
+type AutocompleteChoice struct {
+	Name  string
+	Value string
+}
+
+// Embed is a rich message. Empty fields are left out of the message.
+type Embed struct {
+	Title        string
+	Description  string
+	Color        int
+	ImageURL     string
+	ThumbnailURL string
+	Footer       string
 }
 
 type InteractionCreateHandler func(interaction Interaction)
@@ -163,8 +291,25 @@ type Client interface {
 	AddInteractionCreateHandler(handler InteractionCreateHandler)
 	ListGlobalCommands() ([]RegisteredSlashCommand, error)
 	RegisterGlobalCommand(command SlashCommand) (string, error)
+	UpdateGlobalCommand(commandID string, command SlashCommand) error
+	DeleteGlobalCommand(commandID string) error
+	// BulkOverwriteGlobalCommands replaces every global command with commands
+	// in a single request and returns them as registered.
+	BulkOverwriteGlobalCommands(commands []SlashCommand) ([]RegisteredSlashCommand, error)
+	ListGuildCommands(guildID string) ([]RegisteredSlashCommand, error)
+	RegisterGuildCommand(guildID string, command SlashCommand) (string, error)
+	UpdateGuildCommand(guildID, commandID string, command SlashCommand) error
+	DeleteGuildCommand(guildID, commandID string) error
+	BulkOverwriteGuildCommands(guildID string, commands []SlashCommand) ([]RegisteredSlashCommand, error)
 	RespondToInteraction(interaction Interaction, content string) error
-	SendMessage(channelID, content string) error
+	// RespondWithAutocompleteChoices answers an autocomplete interaction,
+	// keeping the first MaxAutocompleteChoices choices.
+	RespondWithAutocompleteChoices(interaction Interaction, choices []AutocompleteChoice) error
+	// SendMessage and SendEmbed only ping what mentions allows.
+	SendMessage(channelID, content string, mentions AllowedMentions) error
+	// SendEmbed sends embed to the channel, preceded by content when it is
+	// not empty.
+	SendEmbed(channelID, content string, embed Embed, mentions AllowedMentions) error
 }
 
 type discordGoClient struct {
@@ -212,21 +357,25 @@ func (client *discordGoClient) AddMessageCreateHandler(handler MessageCreateHand
 
 func (client *discordGoClient) AddInteractionCreateHandler(handler InteractionCreateHandler) {
 	client.session.AddInteractionCreateHandler(func(interactionCreate *discordgo.InteractionCreate) {
-		if interactionCreate.Type != discordgo.InteractionApplicationCommand {
+		if interactionCreate.Type != discordgo.InteractionApplicationCommand && interactionCreate.Type != discordgo.InteractionApplicationCommandAutocomplete {
 			return
 		}
 
 		interaction := Interaction{
-			ID:          interactionCreate.ID,
-			CommandName: interactionCreate.ApplicationCommandData().Name,
-			ChannelID:   interactionCreate.ChannelID,
-			GuildID:     interactionCreate.GuildID,
-			Options:     make(map[string]string),
-			raw:         interactionCreate.Interaction,
+			ID:           interactionCreate.ID,
+			CommandName:  interactionCreate.ApplicationCommandData().Name,
+			ChannelID:    interactionCreate.ChannelID,
+			GuildID:      interactionCreate.GuildID,
+			Options:      make(map[string]string),
+			Autocomplete: interactionCreate.Type == discordgo.InteractionApplicationCommandAutocomplete,
+			raw:          interactionCreate.Interaction,
 		}
 
 		for _, option := range interactionCreate.ApplicationCommandData().Options {
 			interaction.Options[option.Name] = optionValueToString(option)
+			if option.Focused {
+				interaction.FocusedOption = option.Name
+			}
 		}
 
 		if interactionCreate.Member != nil && interactionCreate.Member.User != nil {
@@ -235,16 +384,73 @@ func (client *discordGoClient) AddInteractionCreateHandler(handler InteractionCr
 			interaction.UserID = interactionCreate.User.ID
 		}
 
+		if interactionCreate.Member != nil {
+			interaction.MemberPermissions = interactionCreate.Member.Permissions
+			interaction.MemberRoleIDs = interactionCreate.Member.Roles
+		}
+
 		handler(interaction)
 	})
 }
 
 func (client *discordGoClient) RegisterGlobalCommand(command SlashCommand) (string, error) {
-	return client.session.ApplicationCommandCreate(command)
+	return client.session.ApplicationCommandCreate("", command)
+}
+
+func (client *discordGoClient) UpdateGlobalCommand(commandID string, command SlashCommand) error {
+	return client.session.ApplicationCommandEdit("", commandID, command)
+}
+
+func (client *discordGoClient) DeleteGlobalCommand(commandID string) error {
+	return client.session.ApplicationCommandDelete("", commandID)
 }
 
 func (client *discordGoClient) ListGlobalCommands() ([]RegisteredSlashCommand, error) {
-	return client.session.ApplicationCommands()
+	return client.session.ApplicationCommands("")
+}
+
+func (client *discordGoClient) BulkOverwriteGlobalCommands(commands []SlashCommand) ([]RegisteredSlashCommand, error) {
+	return client.session.ApplicationCommandBulkOverwrite("", commands)
+}
+
+func (client *discordGoClient) BulkOverwriteGuildCommands(guildID string, commands []SlashCommand) ([]RegisteredSlashCommand, error) {
+	if guildID == "" {
+		return nil, errors.New("missing guild id")
+	}
+
+	return client.session.ApplicationCommandBulkOverwrite(guildID, commands)
+}
+
+func (client *discordGoClient) RegisterGuildCommand(guildID string, command SlashCommand) (string, error) {
+	if guildID == "" {
+		return "", errors.New("missing guild id")
+	}
+
+	return client.session.ApplicationCommandCreate(guildID, command)
+}
+
+func (client *discordGoClient) UpdateGuildCommand(guildID, commandID string, command SlashCommand) error {
+	if guildID == "" {
+		return errors.New("missing guild id")
+	}
+
+	return client.session.ApplicationCommandEdit(guildID, commandID, command)
+}
+
+func (client *discordGoClient) DeleteGuildCommand(guildID, commandID string) error {
+	if guildID == "" {
+		return errors.New("missing guild id")
+	}
+
+	return client.session.ApplicationCommandDelete(guildID, commandID)
+}
+
+func (client *discordGoClient) ListGuildCommands(guildID string) ([]RegisteredSlashCommand, error) {
+	if guildID == "" {
+		return nil, errors.New("missing guild id")
+	}
+
+	return client.session.ApplicationCommands(guildID)
 }
 
 func (client *discordGoClient) RespondToInteraction(interaction Interaction, content string) error {
@@ -255,8 +461,106 @@ func (client *discordGoClient) RespondToInteraction(interaction Interaction, con
 	return client.session.InteractionRespond(interaction.raw, content)
 }
 
-func (client *discordGoClient) SendMessage(channelID, content string) error {
-	return client.session.ChannelMessageSend(channelID, content)
+func (client *discordGoClient) RespondWithAutocompleteChoices(interaction Interaction, choices []AutocompleteChoice) error {
+	if interaction.raw == nil {
+		return errors.New("interaction payload is empty")
+	}
+
+	choices = choices[:min(len(choices), MaxAutocompleteChoices)]
+	trimmed := make([]AutocompleteChoice, 0, len(choices))
+	for _, choice := range choices {
+		if name := []rune(choice.Name); len(name) > maxAutocompleteChoiceNameLength {
+			choice.Name = string(name[:maxAutocompleteChoiceNameLength-1]) + "…"
+		}
+
+		trimmed = append(trimmed, choice)
+	}
+
+	return client.session.InteractionRespondAutocomplete(interaction.raw, trimmed)
+}
+
+func (client *discordGoClient) SendMessage(channelID, content string, mentions AllowedMentions) error {
+	return client.session.ChannelMessageSend(channelID, content, mentions)
+}
+
+func (client *discordGoClient) SendEmbed(channelID, content string, embed Embed, mentions AllowedMentions) error {
+	return client.session.ChannelMessageSendEmbed(channelID, content, embed, mentions)
+}
+
+func toDiscordEmbed(embed Embed) *discordgo.MessageEmbed {
+	messageEmbed := &discordgo.MessageEmbed{
+		Title:       embed.Title,
+		Description: embed.Description,
+		Color:       embed.Color,
+	}
+
+	if embed.ImageURL != "" {
+		messageEmbed.Image = &discordgo.MessageEmbedImage{URL: embed.ImageURL}
+	}
+
+	if embed.ThumbnailURL != "" {
+		messageEmbed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: embed.ThumbnailURL}
+	}
+
+	if embed.Footer != "" {
+		messageEmbed.Footer = &discordgo.MessageEmbedFooter{Text: embed.Footer}
+	}
+
+	return messageEmbed
+}
+
+func toDiscordApplicationCommand(command SlashCommand) *discordgo.ApplicationCommand {
+	options := make([]*discordgo.ApplicationCommandOption, 0, len(command.Options))
+	for _, option := range command.Options {
+		options = append(options, &discordgo.ApplicationCommandOption{
+			Type:         toDiscordOptionType(option.Type),
+			Name:         option.Name,
+			Description:  option.Description,
+			Required:     option.Required,
+			Autocomplete: option.Autocomplete,
+		})
+	}
+
+	applicationCommand := &discordgo.ApplicationCommand{
+		Name:        command.Name,
+		Description: command.Description,
+		Options:     options,
+	}
+
+	if command.DefaultMemberPermissions != 0 {
+		permissions := command.DefaultMemberPermissions
+		applicationCommand.DefaultMemberPermissions = &permissions
+	}
+
+	return applicationCommand
+}
+
+func fromDiscordApplicationCommand(command *discordgo.ApplicationCommand) RegisteredSlashCommand {
+	var options []SlashCommandOption
+	for _, option := range command.Options {
+		options = append(options, SlashCommandOption{
+			Name:         option.Name,
+			Description:  option.Description,
+			Type:         fromDiscordOptionType(option.Type),
+			Required:     option.Required,
+			Autocomplete: option.Autocomplete,
+		})
+	}
+
+	registered := RegisteredSlashCommand{
+		ID: command.ID,
+		SlashCommand: SlashCommand{
+			Name:        command.Name,
+			Description: command.Description,
+			Options:     options,
+		},
+	}
+
+	if command.DefaultMemberPermissions != nil {
+		registered.DefaultMemberPermissions = *command.DefaultMemberPermissions
+	}
+
+	return registered
 }
 
 func toDiscordOptionType(optionType SlashCommandOptionType) discordgo.ApplicationCommandOptionType {
@@ -272,6 +576,19 @@ func toDiscordOptionType(optionType SlashCommandOptionType) discordgo.Applicatio
 	}
 }
 
+func fromDiscordOptionType(optionType discordgo.ApplicationCommandOptionType) SlashCommandOptionType {
+	switch optionType {
+	case discordgo.ApplicationCommandOptionInteger:
+		return SlashCommandOptionTypeInteger
+	case discordgo.ApplicationCommandOptionChannel:
+		return SlashCommandOptionTypeChannel
+	case discordgo.ApplicationCommandOptionRole:
+		return SlashCommandOptionTypeRole
+	default:
+		return SlashCommandOptionTypeString
+	}
+}
+
 func optionValueToString(option *discordgo.ApplicationCommandInteractionDataOption) string {
 	if option == nil {
 		return ""
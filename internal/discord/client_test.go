@@ -2,6 +2,9 @@ package discord
 
 import (
 	"errors"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/bwmarrin/discordgo"
@@ -19,6 +22,13 @@ type fakeSession struct {
 	registeredName        string
 	registeredDescription string
 	registeredOptions     []SlashCommandOption
+	commandGuildID        string
+	editedCommandID       string
+	deletedCommandID      string
+	overwrittenCommands   []SlashCommand
+	sentChoices           []AutocompleteChoice
+	sentEmbed             Embed
+	sentMentions          AllowedMentions
 
 	handler            func(message *discordgo.MessageCreate)
 	interactionHandler func(interaction *discordgo.InteractionCreate)
@@ -40,7 +50,8 @@ func (session *fakeSession) AddInteractionCreateHandler(handler func(interaction
 	session.interactionHandler = handler
 }
 
-func (session *fakeSession) ApplicationCommandCreate(command SlashCommand) (string, error) {
+func (session *fakeSession) ApplicationCommandCreate(guildID string, command SlashCommand) (string, error) {
+	session.commandGuildID = guildID
 	session.registeredName = command.Name
 	session.registeredDescription = command.Description
 	session.registeredOptions = command.Options
@@ -52,18 +63,63 @@ func (session *fakeSession) ApplicationCommandCreate(command SlashCommand) (stri
 	return "command-id-1", nil
 }
 
-func (session *fakeSession) ApplicationCommands() ([]RegisteredSlashCommand, error) {
+func (session *fakeSession) ApplicationCommands(guildID string) ([]RegisteredSlashCommand, error) {
+	session.commandGuildID = guildID
 	return nil, nil
 }
 
+func (session *fakeSession) ApplicationCommandEdit(guildID, commandID string, command SlashCommand) error {
+	session.commandGuildID = guildID
+	session.editedCommandID = commandID
+	session.registeredName = command.Name
+	session.registeredDescription = command.Description
+	session.registeredOptions = command.Options
+	return session.registerErr
+}
+
+func (session *fakeSession) ApplicationCommandBulkOverwrite(guildID string, commands []SlashCommand) ([]RegisteredSlashCommand, error) {
+	session.commandGuildID = guildID
+	session.overwrittenCommands = commands
+	if session.registerErr != nil {
+		return nil, session.registerErr
+	}
+
+	registered := make([]RegisteredSlashCommand, 0, len(commands))
+	for _, command := range commands {
+		registered = append(registered, RegisteredSlashCommand{ID: command.Name + "-id", SlashCommand: command})
+	}
+
+	return registered, nil
+}
+
+func (session *fakeSession) ApplicationCommandDelete(guildID, commandID string) error {
+	session.commandGuildID = guildID
+	session.deletedCommandID = commandID
+	return session.registerErr
+}
+
 func (session *fakeSession) InteractionRespond(interaction *discordgo.Interaction, content string) error {
 	session.sentContent = content
 	return session.respondErr
 }
 
-func (session *fakeSession) ChannelMessageSend(channelID, content string) error {
+func (session *fakeSession) InteractionRespondAutocomplete(interaction *discordgo.Interaction, choices []AutocompleteChoice) error {
+	session.sentChoices = choices
+	return session.respondErr
+}
+
+func (session *fakeSession) ChannelMessageSend(channelID, content string, mentions AllowedMentions) error {
 	session.sentChannelID = channelID
 	session.sentContent = content
+	session.sentMentions = mentions
+	return session.sendErr
+}
+
+func (session *fakeSession) ChannelMessageSendEmbed(channelID, content string, embed Embed, mentions AllowedMentions) error {
+	session.sentChannelID = channelID
+	session.sentContent = content
+	session.sentEmbed = embed
+	session.sentMentions = mentions
 	return session.sendErr
 }
 
@@ -134,7 +190,7 @@ func TestDiscordGoClientSendMessage(t *testing.T) {
 		session := &fakeSession{}
 		client := &discordGoClient{session: session}
 
-		err := client.SendMessage("channel-1", "hello")
+		err := client.SendMessage("channel-1", "hello", AllowedMentions{})
 		if err != nil {
 			t.Fatalf("expected nil error, got %v", err)
 		}
@@ -149,13 +205,68 @@ func TestDiscordGoClientSendMessage(t *testing.T) {
 		session := &fakeSession{sendErr: expectedErr}
 		client := &discordGoClient{session: session}
 
-		err := client.SendMessage("channel-1", "hello")
+		err := client.SendMessage("channel-1", "hello", AllowedMentions{})
 		if !errors.Is(err, expectedErr) {
 			t.Fatalf("expected %v, got %v", expectedErr, err)
 		}
 	})
 }
 
+func TestDiscordGoClientSendEmbed(t *testing.T) {
+	session := &fakeSession{}
+	client := &discordGoClient{session: session}
+	embed := Embed{Title: "Standup", Description: "Daily sync", Color: 0x5865F2, Footer: "Próxima: mañana"}
+
+	mentions := AllowedMentions{RoleIDs: []string{"role-1"}}
+
+	if err := client.SendEmbed("channel-1", "<@&role-1>", embed, mentions); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if session.sentChannelID != "channel-1" || session.sentContent != "<@&role-1>" || session.sentEmbed != embed {
+		t.Fatalf("unexpected send args: channel=%q content=%q embed=%+v", session.sentChannelID, session.sentContent, session.sentEmbed)
+	}
+
+	if !slices.Equal(session.sentMentions.RoleIDs, []string{"role-1"}) {
+		t.Fatalf("unexpected allowed mentions: %+v", session.sentMentions)
+	}
+}
+
+func TestToDiscordEmbed(t *testing.T) {
+	t.Run("omits empty fields", func(t *testing.T) {
+		embed := toDiscordEmbed(Embed{Title: "Standup", Description: "Daily sync"})
+
+		if embed.Title != "Standup" || embed.Description != "Daily sync" || embed.Color != 0 {
+			t.Fatalf("unexpected embed: %+v", embed)
+		}
+
+		if embed.Image != nil || embed.Thumbnail != nil || embed.Footer != nil {
+			t.Fatalf("expected no image, thumbnail or footer, got %+v", embed)
+		}
+	})
+
+	t.Run("includes images and footer", func(t *testing.T) {
+		embed := toDiscordEmbed(Embed{
+			Title:        "Standup",
+			ImageURL:     "https://example.com/image.png",
+			ThumbnailURL: "https://example.com/thumb.png",
+			Footer:       "Próxima: mañana",
+		})
+
+		if embed.Image == nil || embed.Image.URL != "https://example.com/image.png" {
+			t.Fatalf("unexpected image: %+v", embed.Image)
+		}
+
+		if embed.Thumbnail == nil || embed.Thumbnail.URL != "https://example.com/thumb.png" {
+			t.Fatalf("unexpected thumbnail: %+v", embed.Thumbnail)
+		}
+
+		if embed.Footer == nil || embed.Footer.Text != "Próxima: mañana" {
+			t.Fatalf("unexpected footer: %+v", embed.Footer)
+		}
+	})
+}
+
 func TestDiscordGoClientAddMessageCreateHandler(t *testing.T) {
 	t.Run("maps message", func(t *testing.T) {
 		session := &fakeSession{}
@@ -243,6 +354,116 @@ func TestDiscordGoClientRegisterGlobalCommand(t *testing.T) {
 	}
 }
 
+func TestDiscordGoClientUpdateAndDeleteGlobalCommand(t *testing.T) {
+	session := &fakeSession{}
+	client := &discordGoClient{session: session}
+
+	if err := client.UpdateGlobalCommand("command-id-1", SlashCommand{Name: "ping", Description: "Ping"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if session.editedCommandID != "command-id-1" || session.registeredDescription != "Ping" {
+		t.Fatalf("unexpected command update: %q / %q", session.editedCommandID, session.registeredDescription)
+	}
+
+	if err := client.DeleteGlobalCommand("command-id-2"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if session.deletedCommandID != "command-id-2" {
+		t.Fatalf("expected command-id-2 to be deleted, got %q", session.deletedCommandID)
+	}
+}
+
+func TestDiscordGoClientGuildCommands(t *testing.T) {
+	session := &fakeSession{}
+	client := &discordGoClient{session: session}
+
+	if _, err := client.RegisterGuildCommand("guild-1", SlashCommand{Name: "ping"}); err != nil || session.commandGuildID != "guild-1" {
+		t.Fatalf("expected registration in guild-1, got %q (%v)", session.commandGuildID, err)
+	}
+
+	if err := client.UpdateGuildCommand("guild-2", "command-id-1", SlashCommand{Name: "ping"}); err != nil || session.commandGuildID != "guild-2" {
+		t.Fatalf("expected update in guild-2, got %q (%v)", session.commandGuildID, err)
+	}
+
+	if err := client.DeleteGuildCommand("guild-3", "command-id-1"); err != nil || session.commandGuildID != "guild-3" {
+		t.Fatalf("expected delete in guild-3, got %q (%v)", session.commandGuildID, err)
+	}
+
+	if _, err := client.ListGuildCommands("guild-4"); err != nil || session.commandGuildID != "guild-4" {
+		t.Fatalf("expected list in guild-4, got %q (%v)", session.commandGuildID, err)
+	}
+
+	if _, err := client.RegisterGuildCommand("", SlashCommand{Name: "ping"}); err == nil {
+		t.Fatal("expected error registering without a guild id, got nil")
+	}
+}
+
+func TestDiscordGoClientBulkOverwriteCommands(t *testing.T) {
+	session := &fakeSession{}
+	client := &discordGoClient{session: session}
+	commands := []SlashCommand{{Name: "ping"}, {Name: "daily"}}
+
+	registered, err := client.BulkOverwriteGlobalCommands(commands)
+	if err != nil || len(registered) != 2 || registered[1].ID != "daily-id" || session.commandGuildID != "" {
+		t.Fatalf("unexpected global overwrite: %+v in %q (%v)", registered, session.commandGuildID, err)
+	}
+
+	if _, err := client.BulkOverwriteGuildCommands("guild-1", commands); err != nil || session.commandGuildID != "guild-1" || len(session.overwrittenCommands) != 2 {
+		t.Fatalf("unexpected guild overwrite in %q (%v)", session.commandGuildID, err)
+	}
+
+	if _, err := client.BulkOverwriteGuildCommands("", commands); err == nil {
+		t.Fatal("expected error overwriting without a guild id, got nil")
+	}
+}
+
+func TestSlashCommandEqualAndConversion(t *testing.T) {
+	command := SlashCommand{
+		Name:        "setchannel",
+		Description: "Set notification channel",
+		Options: []SlashCommandOption{
+			{Name: "channel", Description: "Channel", Type: SlashCommandOptionTypeChannel, Required: true},
+			{Name: "id", Description: "ID", Type: SlashCommandOptionTypeString, Autocomplete: true},
+		},
+	}
+
+	registered := fromDiscordApplicationCommand(toDiscordApplicationCommand(command))
+	if !registered.Equal(command) {
+		t.Fatalf("expected round-tripped command to be equal, got %+v", registered.SlashCommand)
+	}
+
+	changed := command
+	changed.Options = []SlashCommandOption{{Name: "channel", Description: "Canal", Type: SlashCommandOptionTypeChannel, Required: true}}
+	if command.Equal(changed) {
+		t.Fatal("expected commands with different options not to be equal")
+	}
+
+	changed.Options = []SlashCommandOption{command.Options[0], {Name: "id", Description: "ID", Type: SlashCommandOptionTypeString}}
+	if command.Equal(changed) {
+		t.Fatal("expected commands differing in autocomplete not to be equal")
+	}
+
+	if !(SlashCommand{Name: "ping"}).Equal(SlashCommand{Name: "ping", Options: []SlashCommandOption{}}) {
+		t.Fatal("expected nil and empty options to be equal")
+	}
+
+	if toDiscordApplicationCommand(command).DefaultMemberPermissions != nil {
+		t.Fatal("expected commands without permissions to be open to everyone")
+	}
+
+	gated := command
+	gated.DefaultMemberPermissions = PermissionManageServer
+	if command.Equal(gated) {
+		t.Fatal("expected commands differing in permissions not to be equal")
+	}
+
+	if registered := fromDiscordApplicationCommand(toDiscordApplicationCommand(gated)); !registered.Equal(gated) {
+		t.Fatalf("expected round-tripped permissions, got %+v", registered.SlashCommand)
+	}
+}
+
 func TestDiscordGoClientRespondToInteraction(t *testing.T) {
 	t.Run("missing raw interaction", func(t *testing.T) {
 		client := &discordGoClient{session: &fakeSession{}}
@@ -288,7 +509,9 @@ func TestDiscordGoClientAddInteractionCreateHandler(t *testing.T) {
 			GuildID:   "guild-1",
 			ChannelID: "channel-1",
 			Member: &discordgo.Member{
-				User: &discordgo.User{ID: "user-1"},
+				User:        &discordgo.User{ID: "user-1"},
+				Roles:       []string{"role-1"},
+				Permissions: discordgo.PermissionManageGuild,
 			},
 			Data: discordgo.ApplicationCommandInteractionData{
 				Name: "setchannel",
@@ -311,7 +534,56 @@ func TestDiscordGoClientAddInteractionCreateHandler(t *testing.T) {
 		t.Fatalf("expected user id user-1, got %q", received.UserID)
 	}
 
+	if received.MemberPermissions != PermissionManageServer || !slices.Equal(received.MemberRoleIDs, []string{"role-1"}) {
+		t.Fatalf("expected member permissions and roles, got %d %v", received.MemberPermissions, received.MemberRoleIDs)
+	}
+
 	if received.Options["channel"] != "123456" {
 		t.Fatalf("expected channel option 123456, got %q", received.Options["channel"])
 	}
 }
+
+func TestDiscordGoClientHandlesAutocompleteInteractions(t *testing.T) {
+	session := &fakeSession{}
+	client := &discordGoClient{session: session}
+
+	var received Interaction
+	client.AddInteractionCreateHandler(func(interaction Interaction) {
+		received = interaction
+	})
+
+	session.interactionHandler(&discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      "interaction-2",
+			Type:    discordgo.InteractionApplicationCommandAutocomplete,
+			GuildID: "guild-1",
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name: "delete",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "id", Type: discordgo.ApplicationCommandOptionString, Value: "ab", Focused: true},
+				},
+			},
+		},
+	})
+
+	if !received.Autocomplete || received.CommandName != "delete" || received.FocusedOption != "id" || received.Options["id"] != "ab" {
+		t.Fatalf("unexpected autocomplete interaction: %+v", received)
+	}
+
+	choices := make([]AutocompleteChoice, 0, 30)
+	for index := range 30 {
+		choices = append(choices, AutocompleteChoice{Name: strings.Repeat("x", 120), Value: strconv.Itoa(index)})
+	}
+
+	if err := client.RespondWithAutocompleteChoices(received, choices); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(session.sentChoices) != MaxAutocompleteChoices || len([]rune(session.sentChoices[0].Name)) != 100 {
+		t.Fatalf("expected choices to be capped to Discord limits, got %d choices", len(session.sentChoices))
+	}
+
+	if err := client.RespondWithAutocompleteChoices(Interaction{}, choices); err == nil {
+		t.Fatal("expected error without raw interaction, got nil")
+	}
+}
@@ -0,0 +1,66 @@
+package discord
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// AllowedMentions lists what a sent message may ping. Any other mention in
+// the message, including those written in a notification body, is shown
+// without notifying anyone.
+type AllowedMentions struct {
+	RoleIDs []string
+	UserIDs []string
+	// Everyone allows both @everyone and @here.
+	Everyone bool
+}
+
+// AllowedMentionsFor allows exactly the given mentions, each written as
+// Discord formats it in a message: <@&role>, <@user>, @here or @everyone.
+// Anything else is ignored.
+func AllowedMentionsFor(mentions []string) AllowedMentions {
+	var allowed AllowedMentions
+	for _, mention := range mentions {
+		switch {
+		case mention == "@here" || mention == "@everyone":
+			allowed.Everyone = true
+		case strings.HasPrefix(mention, "<@&") && strings.HasSuffix(mention, ">"):
+			allowed.RoleIDs = append(allowed.RoleIDs, mention[3:len(mention)-1])
+		case strings.HasPrefix(mention, "<@") && strings.HasSuffix(mention, ">"):
+			allowed.UserIDs = append(allowed.UserIDs, strings.TrimPrefix(mention[2:len(mention)-1], "!"))
+		}
+	}
+
+	return allowed
+}
+
+// EscapeMassMentions breaks every @everyone and @here in text that is not in
+// mentions. Discord allows both or neither, so allowing one would otherwise
+// let a message body ping with the other.
+func EscapeMassMentions(text string, mentions []string) string {
+	for _, mention := range []string{"@everyone", "@here"} {
+		if !slices.Contains(mentions, mention) {
+			text = strings.ReplaceAll(text, mention, "@\u200b"+mention[1:])
+		}
+	}
+
+	return text
+}
+
+func toDiscordAllowedMentions(mentions AllowedMentions) *discordgo.MessageAllowedMentions {
+	// A non-nil Parse is sent even when empty, so Discord pings nothing
+	// beyond the listed roles and users.
+	allowed := &discordgo.MessageAllowedMentions{
+		Parse: []discordgo.AllowedMentionType{},
+		Roles: mentions.RoleIDs,
+		Users: mentions.UserIDs,
+	}
+
+	if mentions.Everyone {
+		allowed.Parse = append(allowed.Parse, discordgo.AllowedMentionTypeEveryone)
+	}
+
+	return allowed
+}
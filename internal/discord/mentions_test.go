@@ -0,0 +1,58 @@
+package discord
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAllowedMentionsFor(t *testing.T) {
+	allowed := AllowedMentionsFor([]string{"<@&role-1>", "<@user-1>", "<@!user-2>", "@here", "texto"})
+
+	if !slices.Equal(allowed.RoleIDs, []string{"role-1"}) || !slices.Equal(allowed.UserIDs, []string{"user-1", "user-2"}) || !allowed.Everyone {
+		t.Fatalf("unexpected allowed mentions: %+v", allowed)
+	}
+
+	if allowed := AllowedMentionsFor(nil); allowed.Everyone || allowed.RoleIDs != nil || allowed.UserIDs != nil {
+		t.Fatalf("expected no allowed mentions, got %+v", allowed)
+	}
+}
+
+func TestEscapeMassMentions(t *testing.T) {
+	text := "@everyone y @here"
+
+	if escaped := EscapeMassMentions(text, []string{"@here"}); escaped != "@\u200beveryone y @here" {
+		t.Fatalf("expected only @everyone to be escaped, got %q", escaped)
+	}
+
+	if escaped := EscapeMassMentions(text, nil); escaped != "@\u200beveryone y @\u200bhere" {
+		t.Fatalf("expected both to be escaped, got %q", escaped)
+	}
+
+	if escaped := EscapeMassMentions(text, []string{"@everyone", "@here"}); escaped != text {
+		t.Fatalf("expected nothing to be escaped, got %q", escaped)
+	}
+}
+
+func TestToDiscordAllowedMentions(t *testing.T) {
+	t.Run("allows nothing by default", func(t *testing.T) {
+		allowed := toDiscordAllowedMentions(AllowedMentions{})
+
+		if allowed.Parse == nil || len(allowed.Parse) != 0 || allowed.Roles != nil || allowed.Users != nil {
+			t.Fatalf("expected an explicit empty allow list, got %+v", allowed)
+		}
+	})
+
+	t.Run("allows listed roles users and everyone", func(t *testing.T) {
+		allowed := toDiscordAllowedMentions(AllowedMentions{RoleIDs: []string{"role-1"}, UserIDs: []string{"user-1"}, Everyone: true})
+
+		if !slices.Equal(allowed.Parse, []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeEveryone}) {
+			t.Fatalf("unexpected parse types: %v", allowed.Parse)
+		}
+
+		if !slices.Equal(allowed.Roles, []string{"role-1"}) || !slices.Equal(allowed.Users, []string{"user-1"}) {
+			t.Fatalf("unexpected allowed roles or users: %+v", allowed)
+		}
+	})
+}
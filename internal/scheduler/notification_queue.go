@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"container/heap"
+
+	"github.com/cedaesca/alicia/internal/commands"
+)
+
+// notificationQueue is a min-heap of scheduled notifications ordered by
+// NextNotificationAt, so the earliest one is always at index 0.
+type notificationQueue []commands.ScheduledNotification
+
+// newNotificationQueue leaves out notifications that are still paused when
+// they would fire; a pause with a resume time already moved its notification
+// past the pause, so only open-ended pauses are skipped.
+func newNotificationQueue(notifications []commands.ScheduledNotification) *notificationQueue {
+	queue := make(notificationQueue, 0, len(notifications))
+	for _, notification := range notifications {
+		if !notification.IsPaused(notification.NextNotificationAt) {
+			queue = append(queue, notification)
+		}
+	}
+
+	heap.Init(&queue)
+
+	return &queue
+}
+
+// schedule adds a notification unless it is still paused when it would fire,
+// like newNotificationQueue.
+func (queue *notificationQueue) schedule(notification commands.ScheduledNotification) {
+	if !notification.IsPaused(notification.NextNotificationAt) {
+		heap.Push(queue, notification)
+	}
+}
+
+func (queue notificationQueue) Len() int { return len(queue) }
+
+func (queue notificationQueue) Less(i, j int) bool {
+	return queue[i].NextNotificationAt.Before(queue[j].NextNotificationAt)
+}
+
+func (queue notificationQueue) Swap(i, j int) { queue[i], queue[j] = queue[j], queue[i] }
+
+func (queue *notificationQueue) Push(item any) {
+	*queue = append(*queue, item.(commands.ScheduledNotification))
+}
+
+func (queue *notificationQueue) Pop() any {
+	old := *queue
+	last := old[len(old)-1]
+	*queue = old[:len(old)-1]
+
+	return last
+}
+
+// peek returns the earliest notification without removing it.
+func (queue notificationQueue) peek() (commands.ScheduledNotification, bool) {
+	if len(queue) == 0 {
+		return commands.ScheduledNotification{}, false
+	}
+
+	return queue[0], true
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"container/heap"
+	"testing"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/commands"
+)
+
+func TestNotificationQueuePopsInNextNotificationOrder(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	queue := newNotificationQueue([]commands.ScheduledNotification{
+		{ID: "c", NextNotificationAt: base.Add(3 * time.Hour)},
+		{ID: "a", NextNotificationAt: base.Add(time.Hour)},
+		{ID: "d", NextNotificationAt: base.Add(4 * time.Hour)},
+	})
+	heap.Push(queue, commands.ScheduledNotification{ID: "b", NextNotificationAt: base.Add(2 * time.Hour)})
+
+	if earliest, ok := queue.peek(); !ok || earliest.ID != "a" {
+		t.Fatalf("expected a to be the earliest notification, got %+v", earliest)
+	}
+
+	order := ""
+	for queue.Len() > 0 {
+		order += heap.Pop(queue).(commands.ScheduledNotification).ID
+	}
+
+	if order != "abcd" {
+		t.Fatalf("expected notifications in order abcd, got %s", order)
+	}
+
+	if _, ok := queue.peek(); ok {
+		t.Fatal("expected empty queue")
+	}
+}
+
+func TestNotificationQueueSkipsPausedNotifications(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	queue := newNotificationQueue([]commands.ScheduledNotification{
+		{ID: "paused", Paused: true, NextNotificationAt: base},
+		{ID: "resuming", Paused: true, ResumeAt: base.Add(time.Hour), NextNotificationAt: base.Add(2 * time.Hour)},
+		{ID: "active", NextNotificationAt: base.Add(3 * time.Hour)},
+	})
+
+	order := ""
+	for queue.Len() > 0 {
+		order += heap.Pop(queue).(commands.ScheduledNotification).ID + ","
+	}
+
+	if order != "resuming,active," {
+		t.Fatalf("expected only notifications that fire after their pause, got %s", order)
+	}
+}
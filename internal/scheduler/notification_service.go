@@ -1,26 +1,42 @@
 package scheduler
 
 import (
+	"container/heap"
 	"context"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
+	"github.com/cedaesca/alicia/internal/clock"
 	"github.com/cedaesca/alicia/internal/commands"
 	"github.com/cedaesca/alicia/internal/discord"
 )
 
+// retryDelay is how long the scheduler waits before retrying notifications
+// that are still due after being processed, e.g. when a send failed or the
+// guild has no channel configured.
+const retryDelay = 30 * time.Second
+
+// NotificationService delivers notifications from an in-memory queue ordered
+// by next notification time, sleeping on a single timer until the earliest
+// one is due. A delivered notification is replaced in the queue by its next
+// occurrence; the queue is only reloaded from the store when Wake is called.
 type NotificationService struct {
 	ctx           context.Context
 	logger        *log.Logger
 	discordClient discord.Client
 	store         commands.NotificationConfigStore
-	interval      time.Duration
-	cancel        context.CancelFunc
+	clock         clock.Clock
+	queue         *notificationQueue
+	// retrying holds due notifications whose delivery failed; they go back
+	// into the queue after retryDelay instead of spinning.
+	retrying []commands.ScheduledNotification
+	wake     chan struct{}
+	cancel   context.CancelFunc
 }
 
-func NewNotificationService(ctx context.Context, logger *log.Logger, discordClient discord.Client, store commands.NotificationConfigStore) *NotificationService {
+func NewNotificationService(ctx context.Context, logger *log.Logger, discordClient discord.Client, store commands.NotificationConfigStore, clk clock.Clock) *NotificationService {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -30,7 +46,8 @@ func NewNotificationService(ctx context.Context, logger *log.Logger, discordClie
 		logger:        logger,
 		discordClient: discordClient,
 		store:         store,
-		interval:      30 * time.Second,
+		clock:         clk,
+		wake:          make(chan struct{}, 1),
 	}
 }
 
@@ -43,28 +60,91 @@ func (service *NotificationService) Start() {
 	service.cancel = cancel
 
 	go func() {
-		ticker := time.NewTicker(service.interval)
-		defer ticker.Stop()
-
-		service.processDueNotifications()
+		loaded := service.reloadQueue()
+		timer := service.clock.NewTimer(0)
+		defer timer.Stop()
 
 		for {
 			select {
 			case <-loopCtx.Done():
 				return
-			case <-ticker.C:
-				service.processDueNotifications()
+			case <-timer.C():
+				if !loaded {
+					loaded = service.reloadQueue()
+				}
+
+				if loaded {
+					service.processDueNotifications()
+				}
+			case <-service.wake:
+				if !timer.Stop() {
+					select {
+					case <-timer.C():
+					default:
+					}
+				}
+
+				loaded = service.reloadQueue()
+			}
+
+			if !loaded {
+				timer.Reset(retryDelay)
+			} else if delay, ok := service.nextDelay(); ok {
+				timer.Reset(delay)
 			}
 		}
 	}()
 }
 
-func (service *NotificationService) RecalculateSchedules(ctx context.Context, now time.Time) error {
+// Wake makes the scheduler reload its queue and re-arm its timer. It never
+// blocks; several calls before the scheduler runs collapse into one.
+func (service *NotificationService) Wake() {
+	select {
+	case service.wake <- struct{}{}:
+	default:
+	}
+}
+
+// reloadQueue replaces the queue with every notification in the store,
+// including those waiting to be retried, and reports whether it could.
+func (service *NotificationService) reloadQueue() bool {
+	notifications, err := service.store.ListNotifications(service.ctx)
+	if err != nil {
+		service.logger.Printf("failed to load notification queue: %v", err)
+		return false
+	}
+
+	service.queue = newNotificationQueue(notifications)
+	service.retrying = nil
+
+	return true
+}
+
+// nextDelay returns how long to wait for the earliest notification, or false
+// when there is nothing to wait for.
+func (service *NotificationService) nextDelay() (time.Duration, bool) {
+	earliest, ok := service.queue.peek()
+	switch {
+	case !ok && len(service.retrying) == 0:
+		return 0, false
+	case !ok:
+		return retryDelay, true
+	}
+
+	delay := max(earliest.NextNotificationAt.Sub(service.clock.Now()), 0)
+	if len(service.retrying) > 0 {
+		delay = min(delay, retryDelay)
+	}
+
+	return delay, true
+}
+
+func (service *NotificationService) RecalculateSchedules(ctx context.Context) error {
 	if service.store == nil {
 		return nil
 	}
 
-	return service.store.RecalculateAllNextNotifications(ctx, now)
+	return service.store.RecalculateAllNextNotifications(ctx, service.clock.Now().UTC())
 }
 
 func (service *NotificationService) Stop() {
@@ -73,46 +153,126 @@ func (service *NotificationService) Stop() {
 	}
 }
 
+// processDueNotifications sends every due notification in the queue and
+// queues its next occurrence. A missed occurrence still to be caught up is
+// due again right away, so a catch-up burst is delivered in one go.
 func (service *NotificationService) processDueNotifications() {
-	now := time.Now().UTC()
-	dueNotifications, err := service.store.ListDueNotifications(service.ctx, now)
-	if err != nil {
-		service.logger.Printf("failed to list due notifications: %v", err)
-		return
+	now := service.clock.Now().UTC()
+	for _, notification := range service.retrying {
+		heap.Push(service.queue, notification)
 	}
 
-	for _, notification := range dueNotifications {
-		guildConfig, err := service.store.GetGuildConfig(service.ctx, notification.GuildID)
-		if err != nil {
-			service.logger.Printf("failed to load guild config for notification %s: %v", notification.ID, err)
-			continue
+	service.retrying = nil
+	for {
+		notification, ok := service.queue.peek()
+		if !ok || notification.NextNotificationAt.After(now) {
+			return
 		}
 
-		if strings.TrimSpace(guildConfig.ChannelID) == "" {
-			service.logger.Printf("notification %s skipped: no channel configured", notification.ID)
+		heap.Pop(service.queue)
+		if !service.deliverNotification(notification, now) {
+			service.retrying = append(service.retrying, notification)
 			continue
 		}
 
-		message := formatNotificationMessage(notification, guildConfig.RoleID)
-		if err := service.discordClient.SendMessage(guildConfig.ChannelID, message); err != nil {
-			service.logger.Printf("failed to send notification %s: %v", notification.ID, err)
-			continue
+		if next, ok := notification.Delivered(now); ok {
+			service.queue.schedule(next)
 		}
+	}
+}
 
-		if err := service.store.MarkNotificationSent(service.ctx, notification.ID, now); err != nil {
-			service.logger.Printf("failed to update schedule for notification %s: %v", notification.ID, err)
-			continue
-		}
+// deliverNotification sends the notification and records the delivery in the
+// store, reporting whether both succeeded.
+func (service *NotificationService) deliverNotification(notification commands.ScheduledNotification, now time.Time) bool {
+	guildConfig, err := service.store.GetGuildConfig(service.ctx, notification.GuildID)
+	if err != nil {
+		service.logger.Printf("failed to load guild config for notification %s: %v", notification.ID, err)
+		return false
+	}
+
+	target := notification.EffectiveTarget(guildConfig)
+	if strings.TrimSpace(target.ChannelID) == "" {
+		service.logger.Printf("notification %s skipped: no channel configured", notification.ID)
+		return false
+	}
+
+	if err := service.sendNotification(notification, guildConfig, target, now); err != nil {
+		service.logger.Printf("failed to send notification %s: %v", notification.ID, err)
+		return false
+	}
+
+	if err := service.store.MarkNotificationSent(service.ctx, notification.ID, now); err != nil {
+		service.logger.Printf("failed to update schedule for notification %s: %v", notification.ID, err)
+		return false
+	}
+
+	service.logger.Printf("notification sent: id=%s guild=%s", notification.ID, notification.GuildID)
+	return true
+}
+
+// sendNotification renders the notification templates and delivers it to
+// target as an embed, or as plain text when the guild opted out of embeds.
+// Only the target mentions may ping, whatever the message itself contains.
+func (service *NotificationService) sendNotification(notification commands.ScheduledNotification, guildConfig commands.NotificationConfig, target commands.NotificationTarget, now time.Time) error {
+	notification = commands.RenderNotificationTemplates(notification, guildConfig, now)
+	mentionList := target.MentionList()
+	mentions := strings.Join(mentionList, " ")
+	notification.Title = discord.EscapeMassMentions(notification.Title, mentionList)
+	notification.Message = discord.EscapeMassMentions(notification.Message, mentionList)
+	allowedMentions := discord.AllowedMentionsFor(mentionList)
 
-		service.logger.Printf("notification sent: id=%s guild=%s", notification.ID, notification.GuildID)
+	if guildConfig.PlainText {
+		return service.discordClient.SendMessage(target.ChannelID, formatNotificationMessage(notification, mentions), allowedMentions)
 	}
+
+	return service.discordClient.SendEmbed(target.ChannelID, mentions, formatNotificationEmbed(notification, now), allowedMentions)
 }
 
-func formatNotificationMessage(notification commands.ScheduledNotification, roleID string) string {
+func formatNotificationMessage(notification commands.ScheduledNotification, mentions string) string {
 	prefix := ""
-	if strings.TrimSpace(roleID) != "" {
-		prefix = fmt.Sprintf("<@&%s> ", roleID)
+	if mentions != "" {
+		prefix = mentions + " "
+	}
+
+	message := fmt.Sprintf("%s %s", prefix, notification.Message)
+	if notification.IsCatchingUp() {
+		message += "\n" + formatCatchUpNote(notification)
+	}
+
+	return message
+}
+
+func formatNotificationEmbed(notification commands.ScheduledNotification, sentAt time.Time) discord.Embed {
+	description := notification.Message
+	if notification.IsCatchingUp() {
+		description += "\n\n" + formatCatchUpNote(notification)
+	}
+
+	footer := "Esta fue la última notificación"
+	if next, ok := notification.FollowingNotificationAt(sentAt); ok {
+		location := notificationLocation(notification)
+		footer = fmt.Sprintf("Próxima: %s (%s)", next.In(location).Format("2006-01-02 15:04"), location)
+	}
+
+	return discord.Embed{
+		Title:        notification.Title,
+		Description:  description,
+		Color:        notification.Color,
+		ImageURL:     notification.ImageURL,
+		ThumbnailURL: notification.ThumbnailURL,
+		Footer:       footer,
+	}
+}
+
+func formatCatchUpNote(notification commands.ScheduledNotification) string {
+	return fmt.Sprintf("_(Notificación atrasada: debía enviarse el %s)_", notification.NextNotificationAt.In(notificationLocation(notification)).Format("2006-01-02 15:04"))
+}
+
+func notificationLocation(notification commands.ScheduledNotification) *time.Location {
+	location, err := time.LoadLocation(notification.Timezone)
+	if err != nil {
+		return time.UTC
 	}
 
-	return fmt.Sprintf("%s %s", prefix, notification.Message)
+	return location
 }
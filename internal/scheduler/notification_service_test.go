@@ -1,12 +1,17 @@
 package scheduler
 
 import (
+	"container/heap"
 	"context"
 	"io"
 	"log"
+	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/cedaesca/alicia/internal/clock"
 	"github.com/cedaesca/alicia/internal/commands"
 	"github.com/cedaesca/alicia/internal/discord"
 )
@@ -14,7 +19,12 @@ import (
 type fakeDiscordClient struct {
 	sentChannelID string
 	sentContent   string
+	sentEmbed     discord.Embed
+	sentMentions  discord.AllowedMentions
 	sendCalls     int
+	// sent receives the content of each message and the description of each
+	// embed.
+	sent chan string
 }
 
 func (client *fakeDiscordClient) Open() error { return nil }
@@ -34,23 +44,77 @@ func (client *fakeDiscordClient) RegisterGlobalCommand(command discord.SlashComm
 	return "", nil
 }
 
+func (client *fakeDiscordClient) UpdateGlobalCommand(commandID string, command discord.SlashCommand) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) DeleteGlobalCommand(commandID string) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) BulkOverwriteGlobalCommands(commands []discord.SlashCommand) ([]discord.RegisteredSlashCommand, error) {
+	return nil, nil
+}
+
+func (client *fakeDiscordClient) BulkOverwriteGuildCommands(guildID string, commands []discord.SlashCommand) ([]discord.RegisteredSlashCommand, error) {
+	return nil, nil
+}
+
+func (client *fakeDiscordClient) ListGuildCommands(guildID string) ([]discord.RegisteredSlashCommand, error) {
+	return nil, nil
+}
+
+func (client *fakeDiscordClient) RegisterGuildCommand(guildID string, command discord.SlashCommand) (string, error) {
+	return "", nil
+}
+
+func (client *fakeDiscordClient) UpdateGuildCommand(guildID, commandID string, command discord.SlashCommand) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) DeleteGuildCommand(guildID, commandID string) error {
+	return nil
+}
+
 func (client *fakeDiscordClient) RespondToInteraction(interaction discord.Interaction, content string) error {
 	return nil
 }
 
-func (client *fakeDiscordClient) SendMessage(channelID, content string) error {
+func (client *fakeDiscordClient) RespondWithAutocompleteChoices(interaction discord.Interaction, choices []discord.AutocompleteChoice) error {
+	return nil
+}
+
+func (client *fakeDiscordClient) SendMessage(channelID, content string, mentions discord.AllowedMentions) error {
 	client.sentChannelID = channelID
 	client.sentContent = content
+	client.sentMentions = mentions
 	client.sendCalls++
+	if client.sent != nil {
+		client.sent <- content
+	}
+	return nil
+}
+
+func (client *fakeDiscordClient) SendEmbed(channelID, content string, embed discord.Embed, mentions discord.AllowedMentions) error {
+	client.sentChannelID = channelID
+	client.sentContent = content
+	client.sentEmbed = embed
+	client.sentMentions = mentions
+	client.sendCalls++
+	if client.sent != nil {
+		client.sent <- embed.Description
+	}
 	return nil
 }
 
 type fakeNotificationStore struct {
+	notifications    []commands.ScheduledNotification
 	dueNotifications []commands.ScheduledNotification
 	guildConfig      commands.NotificationConfig
 	markedID         string
 	markedSentAt     time.Time
 	markCalls        int
+	listCalls        int
 }
 
 func (store *fakeNotificationStore) SetChannel(_ context.Context, guildID, channelID string) error {
@@ -61,6 +125,26 @@ func (store *fakeNotificationStore) SetRole(_ context.Context, guildID, roleID s
 	return nil
 }
 
+func (store *fakeNotificationStore) SetTimezone(_ context.Context, guildID, timezone string) error {
+	return nil
+}
+
+func (store *fakeNotificationStore) SetCatchUpPolicy(_ context.Context, guildID, policy string) error {
+	return nil
+}
+
+func (store *fakeNotificationStore) SetPlainText(_ context.Context, guildID string, enabled bool) error {
+	return nil
+}
+
+func (store *fakeNotificationStore) SetMentions(_ context.Context, guildID, mentions string) error {
+	return nil
+}
+
+func (store *fakeNotificationStore) SetManagerRole(_ context.Context, guildID, roleID string) error {
+	return nil
+}
+
 func (store *fakeNotificationStore) AddByMinutesNotification(_ context.Context, guildID string, input commands.ByMinutesNotificationInput) (string, error) {
 	return "", nil
 }
@@ -69,6 +153,26 @@ func (store *fakeNotificationStore) AddDailyNotification(_ context.Context, guil
 	return "", nil
 }
 
+func (store *fakeNotificationStore) AddCronNotification(_ context.Context, guildID string, input commands.CronNotificationInput) (string, error) {
+	return "", nil
+}
+
+func (store *fakeNotificationStore) AddRecurrenceNotification(_ context.Context, guildID string, input commands.RecurrenceNotificationInput) (string, error) {
+	return "", nil
+}
+
+func (store *fakeNotificationStore) AddOnceNotification(_ context.Context, guildID string, input commands.OnceNotificationInput) (string, error) {
+	return "", nil
+}
+
+func (store *fakeNotificationStore) AddWeeklyNotification(_ context.Context, guildID string, input commands.WeeklyNotificationInput) (string, error) {
+	return "", nil
+}
+
+func (store *fakeNotificationStore) AddMonthlyNotification(_ context.Context, guildID string, input commands.MonthlyNotificationInput) (string, error) {
+	return "", nil
+}
+
 func (store *fakeNotificationStore) GetGuildConfig(_ context.Context, guildID string) (commands.NotificationConfig, error) {
 	return store.guildConfig, nil
 }
@@ -84,10 +188,35 @@ func (store *fakeNotificationStore) MarkNotificationSent(_ context.Context, noti
 	return nil
 }
 
+func (store *fakeNotificationStore) ListNotifications(_ context.Context) ([]commands.ScheduledNotification, error) {
+	store.listCalls++
+	return store.notifications, nil
+}
+
 func (store *fakeNotificationStore) ListGuildNotifications(_ context.Context, guildID string) ([]commands.ScheduledNotification, error) {
 	return nil, nil
 }
 
+func (store *fakeNotificationStore) UpdateNotification(_ context.Context, guildID, notificationID string, update commands.NotificationUpdate) (commands.ScheduledNotification, error) {
+	return commands.ScheduledNotification{}, nil
+}
+
+func (store *fakeNotificationStore) PauseNotification(_ context.Context, guildID, notificationID string, resumeAt time.Time) (commands.ScheduledNotification, error) {
+	return commands.ScheduledNotification{}, nil
+}
+
+func (store *fakeNotificationStore) ResumeNotification(_ context.Context, guildID, notificationID string) (commands.ScheduledNotification, error) {
+	return commands.ScheduledNotification{}, nil
+}
+
+func (store *fakeNotificationStore) PauseGuildNotifications(_ context.Context, guildID string, resumeAt time.Time) (int, int, error) {
+	return 0, 0, nil
+}
+
+func (store *fakeNotificationStore) ResumeGuildNotifications(_ context.Context, guildID string) (int, error) {
+	return 0, nil
+}
+
 func (store *fakeNotificationStore) DeleteNotification(_ context.Context, guildID, notificationID string) error {
 	return nil
 }
@@ -116,6 +245,8 @@ func TestProcessDueNotificationsSendsDueNotification(t *testing.T) {
 		logger:        log.New(io.Discard, "", 0),
 		discordClient: client,
 		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.System(),
 	}
 
 	service.processDueNotifications()
@@ -152,6 +283,8 @@ func TestProcessDueNotificationsSendsRecentNotification(t *testing.T) {
 		logger:        log.New(io.Discard, "", 0),
 		discordClient: client,
 		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.System(),
 	}
 
 	service.processDueNotifications()
@@ -188,6 +321,8 @@ func TestProcessDueNotificationsTickSendsStale(t *testing.T) {
 		logger:        log.New(io.Discard, "", 0),
 		discordClient: client,
 		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.System(),
 	}
 
 	service.processDueNotifications()
@@ -204,3 +339,491 @@ func TestProcessDueNotificationsTickSendsStale(t *testing.T) {
 		t.Fatalf("expected marked id n3, got %q", store.markedID)
 	}
 }
+
+func TestProcessDueNotificationsQueuesNextOccurrence(t *testing.T) {
+	now := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{ID: "n1", GuildID: "g1", Type: "byminutes", EveryMinutes: 60, BaseHour: "00:00", Timezone: "UTC", Message: "hourly", NextNotificationAt: now},
+			{ID: "n2", GuildID: "g1", Type: "byminutes", EveryMinutes: 60, BaseHour: "00:30", Timezone: "UTC", Message: "later", NextNotificationAt: now.Add(30 * time.Minute)},
+		},
+		guildConfig: commands.NotificationConfig{ChannelID: "c1"},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.NewFake(now),
+	}
+
+	service.processDueNotifications()
+
+	if client.sendCalls != 1 || store.listCalls != 0 {
+		t.Fatalf("expected one delivery without reloading the store, got %d sends and %d loads", client.sendCalls, store.listCalls)
+	}
+
+	if service.queue.Len() != 2 {
+		t.Fatalf("expected the delivered notification to be queued again, got %d queued", service.queue.Len())
+	}
+
+	if delay, ok := service.nextDelay(); !ok || delay != 30*time.Minute {
+		t.Fatalf("expected to wait for the next notification, got %v %v", delay, ok)
+	}
+
+	heap.Pop(service.queue)
+	if next, _ := service.queue.peek(); next.ID != "n1" || !next.NextNotificationAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected n1 queued an hour later, got %+v", next)
+	}
+}
+
+func TestProcessDueNotificationsRetriesFailedDeliveries(t *testing.T) {
+	now := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{ID: "n1", GuildID: "g1", Type: "daily", BaseHour: "13:00", Timezone: "UTC", Message: "daily", NextNotificationAt: now},
+		},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.NewFake(now),
+	}
+
+	service.processDueNotifications()
+
+	if client.sendCalls != 0 || len(service.retrying) != 1 || service.queue.Len() != 0 {
+		t.Fatalf("expected the undeliverable notification to wait for a retry, got %d sends, %d retrying", client.sendCalls, len(service.retrying))
+	}
+
+	if delay, ok := service.nextDelay(); !ok || delay != retryDelay {
+		t.Fatalf("expected to retry after %v, got %v %v", retryDelay, delay, ok)
+	}
+
+	store.guildConfig = commands.NotificationConfig{ChannelID: "c1"}
+	service.processDueNotifications()
+
+	if client.sendCalls != 1 || store.markedID != "n1" || len(service.retrying) != 0 {
+		t.Fatalf("expected the retry to deliver n1, got %d sends, marked %q", client.sendCalls, store.markedID)
+	}
+}
+
+func TestProcessDueNotificationsSendsEmbed(t *testing.T) {
+	now := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{
+				ID:                 "n4",
+				GuildID:            "g4",
+				Type:               "daily",
+				BaseHour:           "09:00",
+				Timezone:           "America/Caracas",
+				Title:              "Standup #{ocurrencia}",
+				Message:            "daily sync del {dia_semana}",
+				NotificationStyle:  commands.NotificationStyle{Color: 0x5865F2, ImageURL: "https://example.com/standup.png"},
+				NextNotificationAt: now,
+			},
+		},
+		guildConfig: commands.NotificationConfig{ChannelID: "c4", RoleID: "r4"},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.NewFake(now),
+	}
+
+	service.processDueNotifications()
+
+	expected := discord.Embed{
+		Title:       "Standup #1",
+		Description: "daily sync del miércoles",
+		Color:       0x5865F2,
+		ImageURL:    "https://example.com/standup.png",
+		Footer:      "Próxima: 2024-05-02 09:00 (America/Caracas)",
+	}
+	if client.sentChannelID != "c4" || client.sentContent != "<@&r4>" || client.sentEmbed != expected {
+		t.Fatalf("unexpected embed: channel=%q content=%q embed=%+v", client.sentChannelID, client.sentContent, client.sentEmbed)
+	}
+}
+
+func TestProcessDueNotificationsSendsPlainTextWhenConfigured(t *testing.T) {
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{
+				ID:                 "n5",
+				GuildID:            "g5",
+				Type:               "daily",
+				Title:              "Standup",
+				Message:            "daily sync",
+				NextNotificationAt: time.Now().UTC().Add(-time.Minute),
+			},
+		},
+		guildConfig: commands.NotificationConfig{ChannelID: "c5", RoleID: "r5", PlainText: true},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.System(),
+	}
+
+	service.processDueNotifications()
+
+	if client.sentContent != "<@&r5>  daily sync" || client.sentEmbed != (discord.Embed{}) {
+		t.Fatalf("expected a plain text message, got content=%q embed=%+v", client.sentContent, client.sentEmbed)
+	}
+}
+
+func TestProcessDueNotificationsRendersTheMissedOccurrence(t *testing.T) {
+	// Due Wednesday 2024-05-01 09:00 in Caracas, delivered a day late.
+	dueAt := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{
+				ID:                 "n10",
+				GuildID:            "g10",
+				Type:               "daily",
+				BaseHour:           "09:00",
+				Timezone:           "America/Caracas",
+				Message:            "{dia_semana} {fecha} a las {hora}",
+				NextNotificationAt: dueAt,
+			},
+		},
+		guildConfig: commands.NotificationConfig{ChannelID: "c10", PlainText: true},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.NewFake(dueAt.Add(25 * time.Hour)),
+	}
+
+	service.processDueNotifications()
+
+	if client.sentContent != " miércoles 1 de mayo de 2024 a las 09:00" {
+		t.Fatalf("expected the missed occurrence to be rendered, got %q", client.sentContent)
+	}
+}
+
+func TestProcessDueNotificationsPrefersNotificationTarget(t *testing.T) {
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{
+				ID:                 "n6",
+				GuildID:            "g6",
+				Type:               "daily",
+				Message:            "raid",
+				NotificationTarget: commands.NotificationTarget{ChannelID: "raids", RoleID: "raiders"},
+				NextNotificationAt: time.Now().UTC().Add(-time.Minute),
+			},
+		},
+		guildConfig: commands.NotificationConfig{RoleID: "everyone"},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.System(),
+	}
+
+	service.processDueNotifications()
+
+	if client.sentChannelID != "raids" || client.sentContent != "<@&raiders>" {
+		t.Fatalf("expected the notification target, got channel=%q content=%q", client.sentChannelID, client.sentContent)
+	}
+
+	if store.markCalls != 1 {
+		t.Fatalf("expected one mark call, got %d", store.markCalls)
+	}
+}
+
+func TestProcessDueNotificationsOnlyAllowsConfiguredMentions(t *testing.T) {
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{
+				ID:                 "n7",
+				GuildID:            "g7",
+				Type:               "daily",
+				Message:            "{rol} y <@&99> @everyone",
+				NextNotificationAt: time.Now().UTC().Add(-time.Minute),
+			},
+		},
+		guildConfig: commands.NotificationConfig{ChannelID: "c7", RoleID: "1", Mentions: "<@&2> <@3> @here", PlainText: true},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.System(),
+	}
+
+	service.processDueNotifications()
+
+	if client.sentContent != "<@&1> <@&2> <@3> @here  <@&1> y <@&99> @\u200beveryone" {
+		t.Fatalf("unexpected content: %q", client.sentContent)
+	}
+
+	allowed := client.sentMentions
+	if !slices.Equal(allowed.RoleIDs, []string{"1", "2"}) || !slices.Equal(allowed.UserIDs, []string{"3"}) || !allowed.Everyone {
+		t.Fatalf("expected only the configured mentions to be allowed, got %+v", allowed)
+	}
+}
+
+func TestProcessDueNotificationsEscapesUnconfiguredEveryone(t *testing.T) {
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{
+				ID:                 "n9",
+				GuildID:            "g9",
+				Type:               "daily",
+				Title:              "@everyone",
+				Message:            "@everyone @here",
+				NextNotificationAt: time.Now().UTC().Add(-time.Minute),
+			},
+		},
+		guildConfig: commands.NotificationConfig{ChannelID: "c9", Mentions: "@here"},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.System(),
+	}
+
+	service.processDueNotifications()
+
+	if client.sentContent != "@here" || !client.sentMentions.Everyone {
+		t.Fatalf("expected the configured @here to ping, got content=%q mentions=%+v", client.sentContent, client.sentMentions)
+	}
+
+	if client.sentEmbed.Title != "@\u200beveryone" || client.sentEmbed.Description != "@\u200beveryone @here" {
+		t.Fatalf("expected @everyone to be escaped, got title=%q description=%q", client.sentEmbed.Title, client.sentEmbed.Description)
+	}
+}
+
+func TestProcessDueNotificationsAllowsNoMentionsWithoutTargets(t *testing.T) {
+	store := &fakeNotificationStore{
+		dueNotifications: []commands.ScheduledNotification{
+			{ID: "n8", GuildID: "g8", Type: "daily", Message: "<@&99> @everyone", NextNotificationAt: time.Now().UTC().Add(-time.Minute)},
+		},
+		guildConfig: commands.NotificationConfig{ChannelID: "c8"},
+	}
+	client := &fakeDiscordClient{}
+	service := &NotificationService{
+		ctx:           context.Background(),
+		logger:        log.New(io.Discard, "", 0),
+		discordClient: client,
+		store:         store,
+		queue:         newNotificationQueue(store.dueNotifications),
+		clock:         clock.System(),
+	}
+
+	service.processDueNotifications()
+
+	if client.sentContent != "" || client.sentMentions.Everyone || client.sentMentions.RoleIDs != nil || client.sentMentions.UserIDs != nil {
+		t.Fatalf("expected an embed that pings nobody, got content=%q mentions=%+v", client.sentContent, client.sentMentions)
+	}
+}
+
+func TestFormatNotificationEmbedMarksLastOccurrence(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	notification := commands.ScheduledNotification{
+		Type:               "once",
+		Date:               "2024-05-01",
+		BaseHour:           "09:00",
+		Timezone:           "America/Caracas",
+		Title:              "Release",
+		Message:            "deploy",
+		NextNotificationAt: scheduledAt,
+	}
+
+	embed := formatNotificationEmbed(notification, scheduledAt)
+	if embed.Footer != "Esta fue la última notificación" {
+		t.Fatalf("unexpected footer: %q", embed.Footer)
+	}
+}
+
+func TestFormatNotificationEmbedNamesTheFooterTimezone(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	notification := commands.ScheduledNotification{
+		Type:               "daily",
+		BaseHour:           "09:00",
+		Message:            "daily",
+		NextNotificationAt: scheduledAt,
+	}
+
+	if embed := formatNotificationEmbed(notification, scheduledAt); embed.Footer != "Próxima: 2024-05-02 09:00 (UTC)" {
+		t.Fatalf("expected the UTC fallback in the footer, got %q", embed.Footer)
+	}
+
+	notification.Timezone = "America/Caracas"
+	notification.NextNotificationAt = scheduledAt.Add(4 * time.Hour)
+	if embed := formatNotificationEmbed(notification, notification.NextNotificationAt); embed.Footer != "Próxima: 2024-05-02 09:00 (America/Caracas)" {
+		t.Fatalf("expected the notification timezone in the footer, got %q", embed.Footer)
+	}
+}
+
+func TestFormatNotificationMessageMarksLateDelivery(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	notification := commands.ScheduledNotification{
+		Message:            "standup",
+		Timezone:           "America/Caracas",
+		NextNotificationAt: scheduledAt,
+		CatchUpUntil:       scheduledAt,
+	}
+
+	message := formatNotificationMessage(notification, "<@&r1>")
+	expected := "<@&r1>  standup\n_(Notificación atrasada: debía enviarse el 2024-05-01 09:00)_"
+	if message != expected {
+		t.Fatalf("unexpected message: %q", message)
+	}
+
+	notification.CatchUpUntil = time.Time{}
+	if message := formatNotificationMessage(notification, "<@&r1>"); message != "<@&r1>  standup" {
+		t.Fatalf("expected on-time message without late note, got %q", message)
+	}
+}
+
+func TestNotificationServiceWakesForNewNotification(t *testing.T) {
+	store := &addingStore{
+		fakeNotificationStore: &fakeNotificationStore{guildConfig: commands.NotificationConfig{ChannelID: "c1"}},
+		notification: commands.ScheduledNotification{
+			ID:                 "n4",
+			GuildID:            "g1",
+			Type:               "once",
+			Message:            "pronto",
+			NextNotificationAt: time.Now().UTC().Add(50 * time.Millisecond),
+		},
+	}
+	client := &fakeDiscordClient{sent: make(chan string, 1)}
+	service := NewNotificationService(context.Background(), log.New(io.Discard, "", 0), client, store, clock.System())
+
+	service.Start()
+	defer service.Stop()
+
+	_, err := WakeOnChange(store, service).AddOnceNotification(context.Background(), "g1", commands.OnceNotificationInput{})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	select {
+	case content := <-client.sent:
+		if content != "pronto" {
+			t.Fatalf("unexpected content: %q", content)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected notification to be sent after waking the scheduler")
+	}
+}
+
+// addingStore simulates a store that schedules a notification when one is
+// added, guarding its state since the scheduler reads it from its goroutine.
+type addingStore struct {
+	*fakeNotificationStore
+	mu           sync.Mutex
+	notification commands.ScheduledNotification
+	added        bool
+}
+
+func (store *addingStore) AddOnceNotification(_ context.Context, guildID string, input commands.OnceNotificationInput) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.added = true
+	return store.notification.ID, nil
+}
+
+func (store *addingStore) ListNotifications(_ context.Context) ([]commands.ScheduledNotification, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if !store.added {
+		return nil, nil
+	}
+
+	return []commands.ScheduledNotification{store.notification}, nil
+}
+
+func TestNotificationServiceSimulatesAWeekOfDeliveries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFake(start)
+	store := commands.NewJSONNotificationConfigStore(filepath.Join(t.TempDir(), "notification_config.json"), fakeClock)
+	ctx := context.Background()
+
+	if err := store.SetChannel(ctx, "g1", "c1"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	dailyID, err := store.AddDailyNotification(ctx, "g1", commands.DailyNotificationInput{BaseHour: "09:00", Title: "daily", Message: "daily"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if _, err := store.AddByMinutesNotification(ctx, "g1", commands.ByMinutesNotificationInput{EveryMinutes: 360, BaseHour: "03:00", Title: "shift", Message: "shift"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	client := &fakeDiscordClient{sent: make(chan string, 100)}
+	service := NewNotificationService(ctx, log.New(io.Discard, "", 0), client, store, fakeClock)
+	service.Start()
+	defer service.Stop()
+
+	fakeClock.BlockUntilTimers(1)
+	for hour := 0; hour < 7*24; hour++ {
+		fakeClock.Advance(time.Hour)
+		fakeClock.BlockUntilTimers(1)
+	}
+
+	counts := make(map[string]int)
+	for len(client.sent) > 0 {
+		counts[<-client.sent]++
+	}
+
+	if counts["daily"] != 7 || counts["shift"] != 28 {
+		t.Fatalf("expected 7 daily and 28 shift deliveries, got %v", counts)
+	}
+
+	notifications, err := store.ListGuildNotifications(ctx, "g1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	for _, notification := range notifications {
+		if notification.ID != dailyID {
+			continue
+		}
+
+		if expected := time.Date(2024, 1, 7, 9, 0, 0, 0, time.UTC); !notification.LastSentAt.Equal(expected) {
+			t.Fatalf("expected last delivery exactly at %v, got %v", expected, notification.LastSentAt)
+		}
+
+		if expected := time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC); !notification.NextNotificationAt.Equal(expected) {
+			t.Fatalf("expected next delivery at %v, got %v", expected, notification.NextNotificationAt)
+		}
+	}
+}
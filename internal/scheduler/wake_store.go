@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cedaesca/alicia/internal/commands"
+)
+
+// wakeOnChangeStore wraps a notification store and wakes the scheduler after
+// every successful change to the schedule, so its timer is re-armed for the
+// new earliest notification.
+type wakeOnChangeStore struct {
+	commands.NotificationConfigStore
+	service *NotificationService
+}
+
+// WakeOnChange returns a store that forwards to store and calls service.Wake
+// whenever a notification is added, edited, paused, resumed, deleted or
+// rescheduled.
+func WakeOnChange(store commands.NotificationConfigStore, service *NotificationService) commands.NotificationConfigStore {
+	return &wakeOnChangeStore{NotificationConfigStore: store, service: service}
+}
+
+func (store *wakeOnChangeStore) SetTimezone(ctx context.Context, guildID, timezone string) error {
+	return store.wakeAfter(store.NotificationConfigStore.SetTimezone(ctx, guildID, timezone))
+}
+
+func (store *wakeOnChangeStore) AddByMinutesNotification(ctx context.Context, guildID string, input commands.ByMinutesNotificationInput) (string, error) {
+	return store.wakeAfterAdd(store.NotificationConfigStore.AddByMinutesNotification(ctx, guildID, input))
+}
+
+func (store *wakeOnChangeStore) AddDailyNotification(ctx context.Context, guildID string, input commands.DailyNotificationInput) (string, error) {
+	return store.wakeAfterAdd(store.NotificationConfigStore.AddDailyNotification(ctx, guildID, input))
+}
+
+func (store *wakeOnChangeStore) AddCronNotification(ctx context.Context, guildID string, input commands.CronNotificationInput) (string, error) {
+	return store.wakeAfterAdd(store.NotificationConfigStore.AddCronNotification(ctx, guildID, input))
+}
+
+func (store *wakeOnChangeStore) AddRecurrenceNotification(ctx context.Context, guildID string, input commands.RecurrenceNotificationInput) (string, error) {
+	return store.wakeAfterAdd(store.NotificationConfigStore.AddRecurrenceNotification(ctx, guildID, input))
+}
+
+func (store *wakeOnChangeStore) AddOnceNotification(ctx context.Context, guildID string, input commands.OnceNotificationInput) (string, error) {
+	return store.wakeAfterAdd(store.NotificationConfigStore.AddOnceNotification(ctx, guildID, input))
+}
+
+func (store *wakeOnChangeStore) AddWeeklyNotification(ctx context.Context, guildID string, input commands.WeeklyNotificationInput) (string, error) {
+	return store.wakeAfterAdd(store.NotificationConfigStore.AddWeeklyNotification(ctx, guildID, input))
+}
+
+func (store *wakeOnChangeStore) AddMonthlyNotification(ctx context.Context, guildID string, input commands.MonthlyNotificationInput) (string, error) {
+	return store.wakeAfterAdd(store.NotificationConfigStore.AddMonthlyNotification(ctx, guildID, input))
+}
+
+func (store *wakeOnChangeStore) UpdateNotification(ctx context.Context, guildID, notificationID string, update commands.NotificationUpdate) (commands.ScheduledNotification, error) {
+	notification, err := store.NotificationConfigStore.UpdateNotification(ctx, guildID, notificationID, update)
+	return notification, store.wakeAfter(err)
+}
+
+func (store *wakeOnChangeStore) PauseNotification(ctx context.Context, guildID, notificationID string, resumeAt time.Time) (commands.ScheduledNotification, error) {
+	notification, err := store.NotificationConfigStore.PauseNotification(ctx, guildID, notificationID, resumeAt)
+	return notification, store.wakeAfter(err)
+}
+
+func (store *wakeOnChangeStore) ResumeNotification(ctx context.Context, guildID, notificationID string) (commands.ScheduledNotification, error) {
+	notification, err := store.NotificationConfigStore.ResumeNotification(ctx, guildID, notificationID)
+	return notification, store.wakeAfter(err)
+}
+
+func (store *wakeOnChangeStore) PauseGuildNotifications(ctx context.Context, guildID string, resumeAt time.Time) (int, int, error) {
+	paused, skipped, err := store.NotificationConfigStore.PauseGuildNotifications(ctx, guildID, resumeAt)
+	return paused, skipped, store.wakeAfter(err)
+}
+
+func (store *wakeOnChangeStore) ResumeGuildNotifications(ctx context.Context, guildID string) (int, error) {
+	count, err := store.NotificationConfigStore.ResumeGuildNotifications(ctx, guildID)
+	return count, store.wakeAfter(err)
+}
+
+func (store *wakeOnChangeStore) DeleteNotification(ctx context.Context, guildID, notificationID string) error {
+	return store.wakeAfter(store.NotificationConfigStore.DeleteNotification(ctx, guildID, notificationID))
+}
+
+func (store *wakeOnChangeStore) RecalculateAllNextNotifications(ctx context.Context, now time.Time) error {
+	return store.wakeAfter(store.NotificationConfigStore.RecalculateAllNextNotifications(ctx, now))
+}
+
+func (store *wakeOnChangeStore) wakeAfter(err error) error {
+	// A notification retired on resume changed the schedule too.
+	if err == nil || errors.Is(err, commands.ErrNotificationExpired) {
+		store.service.Wake()
+	}
+
+	return err
+}
+
+func (store *wakeOnChangeStore) wakeAfterAdd(id string, err error) (string, error) {
+	return id, store.wakeAfter(err)
+}